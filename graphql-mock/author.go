@@ -1,131 +0,0 @@
-package main
-
-import (
-	"encoding/json"
-	"errors"
-	"fmt"
-	"github.com/dgrijalva/jwt-go"
-	"github.com/graphql-go/graphql"
-	"github.com/mitchellh/mapstructure"
-	uuid "github.com/satori/go.uuid"
-	"golang.org/x/crypto/bcrypt"
-	"gopkg.in/go-playground/validator.v9"
-	"net/http"
-	"time"
-)
-
-type Author struct {
-	Id        string `json:"id,omitempty" validate:"omitempty,uuid"`
-	Firstname string `json:"firstname,omitempty" validate:"required"`
-	Lastname  string `json:"lastname,omitempty" validate:"required"`
-	Username  string `json:"username,omitempty" validate:"required"`
-	Password  string `json:"password,omitempty" validate:"required,gte=4"`
-}
-
-var authorType *graphql.Object = graphql.NewObject(graphql.ObjectConfig{
-	Name: "Author",
-	Fields: graphql.Fields{
-		"id": &graphql.Field{
-			Type: graphql.String,
-		},
-		"firstname": &graphql.Field{
-			Type: graphql.String,
-		},
-		"lastname": &graphql.Field{
-			Type: graphql.String,
-		},
-		"username": &graphql.Field{
-			Type: graphql.String,
-		},
-		"password": &graphql.Field{
-			Type: graphql.String,
-		},
-	},
-})
-
-var authorInputType *graphql.InputObject = graphql.NewInputObject(graphql.InputObjectConfig{
-	Name: "AuthorInput",
-	Fields: graphql.InputObjectConfigFieldMap{
-		"id": &graphql.InputObjectFieldConfig{
-			Type: graphql.String,
-		},
-		"firstname": &graphql.InputObjectFieldConfig{
-			Type: graphql.String,
-		},
-		"lastname": &graphql.InputObjectFieldConfig{
-			Type: graphql.String,
-		},
-		"username": &graphql.InputObjectFieldConfig{
-			Type: graphql.String,
-		},
-		"password": &graphql.InputObjectFieldConfig{
-			Type: graphql.String,
-		},
-	},
-})
-
-func RegisterEndpoint(response http.ResponseWriter, request *http.Request) {
-	response.Header().Add("content-type", "application/json")
-	var author Author
-	json.NewDecoder(request.Body).Decode(&author)
-	validate := validator.New()
-	err := validate.Struct(author)
-
-	if err != nil {
-		response.WriteHeader(500)
-		response.Write([]byte(`{ "message": "` + err.Error() + `" }`))
-		return
-	}
-	hash, _ := bcrypt.GenerateFromPassword([]byte(author.Password), 10)
-	author.Id = uuid.Must(uuid.NewV4()).String()
-	author.Password = string(hash)
-	authors = append(authors, author)
-	json.NewEncoder(response).Encode(authors)
-}
-
-func LoginEndpoint(response http.ResponseWriter, request *http.Request) {
-	response.Header().Add("content-type", "application/json")
-	var data Author
-	json.NewDecoder(request.Body).Decode(&data)
-	for _, author := range authors {
-		if author.Username == data.Username {
-			err := bcrypt.CompareHashAndPassword([]byte(author.Password), []byte(data.Password))
-			if err != nil {
-				response.WriteHeader(500)
-				response.Write([]byte(`{ "message": "invalid password" }`))
-				return
-			}
-			claims := CustomJWTClaims{
-				Id: author.Id,
-				StandardClaims: jwt.StandardClaims{
-					ExpiresAt: time.Now().Local().Add(time.Hour).Unix(),
-					Issuer:    "Go Test",
-				},
-			}
-			token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-			tokenString, _ := token.SignedString(JwtSecret)
-			response.Write([]byte(`{ "token": "` + tokenString + `" }`))
-			return
-		}
-	}
-	response.Write([]byte(`{ "message": "invalid username" }`))
-}
-
-func ValidateJWT(t string) (interface{}, error) {
-	token, err := jwt.Parse(t, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("Unexpected signing method %v", token.Header["alg"])
-		}
-		return JwtSecret, nil
-	})
-	if err != nil {
-		return nil, errors.New(`{ "message": "` + err.Error() + `"}`)
-	}
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		var tokenData CustomJWTClaims
-		mapstructure.Decode(claims, &tokenData)
-		return tokenData, nil
-	} else {
-		return nil, errors.New(`{ "message": "invalid token" }`)
-	}
-}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/Bone1289/go-web-example/mock/gql"
+	"github.com/Bone1289/go-web-example/mock/mocktest"
+)
+
+type authorArticles struct {
+	Username     string
+	ArticleCount int
+	Articles     struct {
+		TotalCount int
+		Edges      []struct {
+			Node struct {
+				Id     string
+				Author struct{ Username string }
+			}
+		}
+		PageInfo struct {
+			HasNextPage bool
+			EndCursor   string
+		}
+	}
+}
+
+func TestAuthorArticles(t *testing.T) {
+	server := newServer(t, "memory")
+	writer, _ := mocktest.CreateAndLogin(t, server, "writer")
+	other, _ := mocktest.CreateAndLogin(t, server, "other")
+	mocktest.CreateAndLogin(t, server, "idle")
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for index, article := range []mock.Article{
+		{Id: "writer-1", Author: writer.Id},
+		{Id: "other-1", Author: other.Id},
+		{Id: "writer-2", Author: writer.Id},
+		{Id: "writer-3", Author: writer.Id},
+	} {
+		article.Title, article.Content = article.Id, "content"
+		article.CreatedAt = created.Add(time.Duration(index) * time.Hour)
+		if err := mock.Articles.Create(article); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page := func(after string) map[string]authorArticles {
+		result := mocktest.Query(t, server, "", gql.GraphQLPayload{
+			Query: `query($after: String) { authors(filter: {lastname: "Test"}) { edges { node {
+				username articleCount
+				articles(first: 2, after: $after, orderBy: [{field: CREATED_AT}]) {
+					totalCount edges { node { id author { username } } } pageInfo { hasNextPage endCursor }
+				}
+			} } } }`,
+			Variables: map[string]interface{}{"after": after},
+		})
+		var connection struct {
+			Edges []struct{ Node authorArticles }
+		}
+		json.Unmarshal(result.Data["authors"], &connection)
+		authors := map[string]authorArticles{}
+		for _, edge := range connection.Edges {
+			authors[edge.Node.Username] = edge.Node
+		}
+		return authors
+	}
+	ids := func(author authorArticles) []string {
+		ids := []string{}
+		for _, edge := range author.Articles.Edges {
+			if edge.Node.Author.Username != author.Username {
+				t.Errorf("%s: article %s by %s", author.Username, edge.Node.Id, edge.Node.Author.Username)
+			}
+			ids = append(ids, edge.Node.Id)
+		}
+		return ids
+	}
+
+	authors := page("")
+	if len(authors) != 3 {
+		t.Fatalf("expected 3 authors, got %+v", authors)
+	}
+	for username, expected := range map[string]int{"writer": 3, "other": 1, "idle": 0} {
+		author := authors[username]
+		if author.ArticleCount != expected || author.Articles.TotalCount != expected {
+			t.Errorf("%s: expected %d articles, got articleCount %d and totalCount %d", username, expected, author.ArticleCount, author.Articles.TotalCount)
+		}
+	}
+	writerPage := authors["writer"]
+	if got := ids(writerPage); len(got) != 2 || got[0] != "writer-1" || got[1] != "writer-2" || !writerPage.Articles.PageInfo.HasNextPage {
+		t.Errorf("writer, first page: got %v, %+v", got, writerPage.Articles.PageInfo)
+	}
+	if got := ids(authors["other"]); len(got) != 1 || got[0] != "other-1" {
+		t.Errorf("other: got %v", got)
+	}
+	if got := ids(authors["idle"]); len(got) != 0 {
+		t.Errorf("idle: got %v", got)
+	}
+
+	writerPage = page(writerPage.Articles.PageInfo.EndCursor)["writer"]
+	if got := ids(writerPage); len(got) != 1 || got[0] != "writer-3" || writerPage.Articles.PageInfo.HasNextPage || writerPage.ArticleCount != 3 {
+		t.Errorf("writer, second page: got %v, %+v", got, writerPage.Articles)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock/gql"
+	"github.com/Bone1289/go-web-example/mock/mocktest"
+)
+
+func TestConnectionLimits(t *testing.T) {
+	server := newServer(t, "memory")
+	mocktest.CreateAndLogin(t, server, "limits")
+
+	for _, arguments := range []string{"first: 0", "last: 0", "first: -1", "last: -1"} {
+		result := mocktest.QueryResult(t, server, "", gql.GraphQLPayload{
+			Query: `{ authors(` + arguments + `) { edges { node { id } } } }`,
+		})
+		if len(result.Errors) != 1 || result.Errors[0].Extensions.Code != "bad_request" {
+			t.Errorf("%s: expected a bad_request error, got %+v", arguments, result.Errors)
+		}
+	}
+
+	result := mocktest.Query(t, server, "", gql.GraphQLPayload{
+		Query: `{ authors(first: 1) { totalCount edges { node { id } } pageInfo { hasNextPage } } }`,
+	})
+	var connection struct {
+		TotalCount int
+		Edges      []interface{}
+		PageInfo   struct{ HasNextPage bool }
+	}
+	json.Unmarshal(result.Data["authors"], &connection)
+	if len(connection.Edges) != 1 || connection.TotalCount < 2 || !connection.PageInfo.HasNextPage {
+		t.Errorf("first: 1: unexpected connection %+v", connection)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/Bone1289/go-web-example/mock/gql"
+	"github.com/Bone1289/go-web-example/mock/mocktest"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestErrorExtensions(t *testing.T) {
+	cost := mock.BcryptCost
+	mock.BcryptCost = bcrypt.MinCost
+	t.Cleanup(func() { mock.BcryptCost = cost })
+	server := newServer(t, "memory")
+	_, token := mocktest.CreateAndLogin(t, server, "errors")
+
+	tests := []struct {
+		name    string
+		query   string
+		code    string
+		details []mock.FieldError
+	}{
+		{
+			name:    "createArticle without a title",
+			query:   `mutation { createArticle(article: {content: "content"}) { id } }`,
+			code:    "validation_failed",
+			details: []mock.FieldError{{Field: "title", Rule: "required"}},
+		},
+		{
+			name:    "register with a short password",
+			query:   `mutation { register(author: {firstname: "F", lastname: "L", username: "short", password: "abc"}) { id } }`,
+			code:    "validation_failed",
+			details: []mock.FieldError{{Field: "password", Rule: "gte", Param: "4"}},
+		},
+		{
+			name:  "register a taken username",
+			query: `mutation { register(author: {firstname: "F", lastname: "L", username: "errors", password: "secret"}) { id } }`,
+			code:  "conflict",
+		},
+		{
+			name:  "login with a wrong password",
+			query: `mutation { login(username: "errors", password: "wrong") { token } }`,
+			code:  "invalid_credentials",
+		},
+		{
+			name:  "invalid cursor",
+			query: `{ articles(first: 1, after: "not-a-cursor") { totalCount } }`,
+			code:  "bad_request",
+		},
+	}
+	for _, test := range tests {
+		result := mocktest.QueryResult(t, server, token, gql.GraphQLPayload{Query: test.query})
+		if len(result.Errors) != 1 {
+			t.Errorf("%s: expected one error, got %+v", test.name, result.Errors)
+			continue
+		}
+		extensions := result.Errors[0].Extensions
+		if extensions.Code != test.code {
+			t.Errorf("%s: expected code %q, got %q", test.name, test.code, extensions.Code)
+		}
+		if len(extensions.Details) != len(test.details) {
+			t.Errorf("%s: expected details %+v, got %+v", test.name, test.details, extensions.Details)
+			continue
+		}
+		for index, detail := range extensions.Details {
+			expected := test.details[index]
+			if detail.Field != expected.Field || detail.Rule != expected.Rule || detail.Param != expected.Param || detail.Message == "" {
+				t.Errorf("%s: expected detail %+v, got %+v", test.name, expected, detail)
+			}
+		}
+	}
+}
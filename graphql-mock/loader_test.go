@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/Bone1289/go-web-example/mock/gql"
+)
+
+const loaderAuthors = 100
+const loaderArticles = 1000
+
+// countingAuthorStore counts the lookups the resolvers make, so the benchmark
+// can show that a whole query needs a single batched one.
+type countingAuthorStore struct {
+	mock.AuthorStore
+	gets     int64
+	getManys int64
+}
+
+func (store *countingAuthorStore) Get(id string) (mock.Author, error) {
+	atomic.AddInt64(&store.gets, 1)
+	return store.AuthorStore.Get(id)
+}
+
+func (store *countingAuthorStore) GetMany(ids []string) ([]mock.Author, error) {
+	atomic.AddInt64(&store.getManys, 1)
+	return store.AuthorStore.GetMany(ids)
+}
+
+func BenchmarkArticleAuthors(b *testing.B) {
+	var authors []mock.Author
+	var articles []mock.Article
+	for index := 0; index < loaderAuthors; index++ {
+		authors = append(authors, mock.Author{
+			Id:       fmt.Sprintf("author-%d", index),
+			Username: fmt.Sprintf("author-%d", index),
+		})
+	}
+	for index := 0; index < loaderArticles; index++ {
+		articles = append(articles, mock.Article{
+			Id:     fmt.Sprintf("article-%d", index),
+			Author: authors[index%loaderAuthors].Id,
+			Title:  "title",
+		})
+	}
+	store := &countingAuthorStore{AuthorStore: mock.NewMemoryAuthorStore(authors)}
+	mock.Authors = store
+	mock.Articles = mock.NewMemoryArticleStore(articles)
+	router, err := NewRouter()
+	if err != nil {
+		b.Fatal(err)
+	}
+	payload, _ := json.Marshal(gql.GraphQLPayload{
+		Query: fmt.Sprintf(`{ articles(first: %d) { edges { node { title author { id username articleCount } } } } }`, loaderArticles),
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for round := 0; round < b.N; round++ {
+		request := httptest.NewRequest("POST", "/graphql", bytes.NewReader(payload))
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		if recorder.Code != http.StatusOK {
+			b.Fatalf("unexpected status %d", recorder.Code)
+		}
+	}
+	b.StopTimer()
+
+	if store.gets != 0 || store.getManys != int64(b.N) {
+		b.Fatalf("expected one batched lookup per query, got %d gets and %d batches in %d queries", store.gets, store.getManys, b.N)
+	}
+	b.ReportMetric(float64(store.getManys+store.gets)/float64(b.N), "lookups/op")
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock/mocktest"
+)
+
+// newServer serves the GraphQL API over new seeded stores of kind.
+func newServer(t *testing.T, kind string) *httptest.Server {
+	router, err := NewRouter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mocktest.NewServer(t, kind, router)
+}
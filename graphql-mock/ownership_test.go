@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/Bone1289/go-web-example/mock/gql"
+	"github.com/Bone1289/go-web-example/mock/mocktest"
+)
+
+func TestAuthorOwnership(t *testing.T) {
+	server := newServer(t, "memory")
+	owner, ownerToken := mocktest.CreateAndLogin(t, server, "owner")
+	_, otherToken := mocktest.CreateAndLogin(t, server, "other")
+
+	update := `mutation { updateAuthor(author: {id: "` + owner.Id + `", firstname: "Changed"}) { id } }`
+	remove := `mutation { deleteAuthor(id: "` + owner.Id + `") { id } }`
+	tests := []struct {
+		name  string
+		query string
+		token string
+		code  string
+	}{
+		{"updateAuthor without a token", update, "", "unauthorized"},
+		{"deleteAuthor without a token", remove, "", "unauthorized"},
+		{"updateAuthor by another author", update, otherToken, "forbidden"},
+		{"deleteAuthor by another author", remove, otherToken, "forbidden"},
+		{"updateAuthor by the owner", update, ownerToken, ""},
+		{"deleteAuthor by the owner", remove, ownerToken, ""},
+	}
+	for _, test := range tests {
+		result := mocktest.QueryResult(t, server, test.token, gql.GraphQLPayload{Query: test.query})
+		code := ""
+		if len(result.Errors) > 0 {
+			code = result.Errors[0].Extensions.Code
+		}
+		if code != test.code || len(result.Errors) > 1 {
+			t.Errorf("%s: expected code %q, got %+v", test.name, test.code, result.Errors)
+		}
+	}
+	if _, err := mock.Authors.Get(owner.Id); err != mock.ErrNotFound {
+		t.Errorf("owner could not delete themselves: %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/Bone1289/go-web-example/mock/gql"
+	"github.com/Bone1289/go-web-example/mock/mocktest"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestAuthorTypeHasNoPassword(t *testing.T) {
+	server := newServer(t, "memory")
+	result := mocktest.Query(t, server, "", gql.GraphQLPayload{
+		Query: `{ __type(name: "Author") { fields { name } } }`,
+	})
+	if strings.Contains(string(result.Data["__type"]), `"password"`) {
+		t.Errorf("Author exposes a password field: %s", result.Data["__type"])
+	}
+}
+
+func TestNoPasswordInResponses(t *testing.T) {
+	cost := mock.BcryptCost
+	mock.BcryptCost = bcrypt.MinCost
+	t.Cleanup(func() { mock.BcryptCost = cost })
+	server := newServer(t, "memory")
+	author, token := mocktest.CreateAndLogin(t, server, "owner")
+
+	fields := `id firstname lastname username role createdAt articleCount`
+	payloads := []gql.GraphQLPayload{
+		{Query: `mutation { register(author: {firstname: "New", lastname: "Author", username: "new", password: "secret"}) { ` + fields + ` } }`},
+		{Query: `mutation { login(username: "owner", password: "secret") { token refreshToken } }`},
+		{Query: `{ authors { edges { node { ` + fields + ` } } } }`},
+		{Query: `{ author(id: "` + author.Id + `") { ` + fields + ` } }`},
+		{Query: `{ articles { edges { node { author { ` + fields + ` } } } } }`},
+		{Query: `mutation { updateAuthor(author: {id: "` + author.Id + `", password: "changed"}) { ` + fields + ` } }`},
+		{Query: `mutation { deleteAuthor(id: "` + author.Id + `") { ` + fields + ` } }`},
+	}
+	for _, payload := range payloads {
+		result := mocktest.Query(t, server, token, payload)
+		for name, data := range result.Data {
+			if strings.Contains(string(data), "$2a$") {
+				t.Errorf("%s exposed a password hash: %s", name, data)
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/Bone1289/go-web-example/mock/gql"
+	"github.com/Bone1289/go-web-example/mock/mocktest"
+)
+
+func TestPolicy(t *testing.T) {
+	server := newServer(t, "memory")
+	owner, ownerToken := mocktest.CreateAndLogin(t, server, "owner")
+	_, readerToken := mocktest.CreateWithRole(t, server, "reader", mock.RoleReader)
+	_, editorToken := mocktest.CreateWithRole(t, server, "editor", mock.RoleEditor)
+	_, adminToken := mocktest.CreateWithRole(t, server, "admin", mock.RoleAdmin)
+	article, err := mock.CreateArticle(mock.CustomJWTClaims{Id: owner.Id}, mock.Article{Title: "title", Content: "content"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		token string
+		code  string
+	}{
+		{"reader creates an article", `mutation { createArticle(article: {title: "title", content: "content"}) { id } }`, readerToken, "forbidden"},
+		{"editor updates another author's article", `mutation { updateArticle(id: "` + article.Id + `", article: {title: "edited"}) { id } }`, editorToken, ""},
+		{"author changes their own role", `mutation { updateAuthor(author: {id: "` + owner.Id + `", role: "admin"}) { id } }`, ownerToken, "forbidden"},
+		{"admin deletes another author", `mutation { deleteAuthor(id: "` + owner.Id + `") { id } }`, adminToken, ""},
+	}
+	for _, test := range tests {
+		result := mocktest.QueryResult(t, server, test.token, gql.GraphQLPayload{Query: test.query})
+		code := ""
+		if len(result.Errors) > 0 {
+			code = result.Errors[0].Extensions.Code
+		}
+		if code != test.code || len(result.Errors) > 1 {
+			t.Errorf("%s: expected code %q, got %+v", test.name, test.code, result.Errors)
+		}
+	}
+	if edited, _ := mock.Articles.Get(article.Id); edited.Title != "edited" {
+		t.Errorf("editor's update was not stored: %+v", edited)
+	}
+	if _, err := mock.Authors.Get(owner.Id); err != mock.ErrNotFound {
+		t.Errorf("admin could not delete the author: %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/Bone1289/go-web-example/mock/gql"
+	"github.com/Bone1289/go-web-example/mock/mocktest"
+)
+
+func TestSessionMutations(t *testing.T) {
+	server := newServer(t, "memory")
+	mocktest.CreateAndLogin(t, server, "session")
+
+	var tokens mock.Tokens
+	result := mocktest.Query(t, server, "", gql.GraphQLPayload{
+		Query: `mutation { login(username: "session", password: "secret") { token refreshToken } }`,
+	})
+	json.Unmarshal(result.Data["login"], &tokens)
+	if tokens.Token == "" || tokens.RefreshToken == "" {
+		t.Fatalf("login returned %s", result.Data["login"])
+	}
+
+	refresh := gql.GraphQLPayload{
+		Query:     `mutation($refreshToken: String!) { refreshToken(refreshToken: $refreshToken) { token refreshToken } }`,
+		Variables: map[string]interface{}{"refreshToken": tokens.RefreshToken},
+	}
+	var refreshed mock.Tokens
+	result = mocktest.Query(t, server, "", refresh)
+	json.Unmarshal(result.Data["refreshToken"], &refreshed)
+	if refreshed.Token == "" || refreshed.RefreshToken == "" || refreshed.RefreshToken == tokens.RefreshToken {
+		t.Fatalf("refreshToken returned %s", result.Data["refreshToken"])
+	}
+	result = mocktest.QueryResult(t, server, "", refresh)
+	if len(result.Errors) != 1 || result.Errors[0].Extensions.Code != "unauthorized" {
+		t.Errorf("reused refresh token: expected unauthorized, got %+v", result.Errors)
+	}
+
+	logout := gql.GraphQLPayload{
+		Query:     `mutation($refreshToken: String) { logout(refreshToken: $refreshToken) }`,
+		Variables: map[string]interface{}{"refreshToken": refreshed.RefreshToken},
+	}
+	result = mocktest.QueryResult(t, server, "", logout)
+	if len(result.Errors) != 1 || result.Errors[0].Extensions.Code != "unauthorized" {
+		t.Errorf("logout without a token: expected unauthorized, got %+v", result.Errors)
+	}
+	result = mocktest.Query(t, server, refreshed.Token, logout)
+	if string(result.Data["logout"]) != "true" {
+		t.Errorf("logout returned %s", result.Data["logout"])
+	}
+	result = mocktest.QueryResult(t, server, refreshed.Token, gql.GraphQLPayload{
+		Query: `mutation { createArticle(article: {title: "title", content: "content"}) { id } }`,
+	})
+	if len(result.Errors) != 1 || result.Errors[0].Extensions.Code != "unauthorized" {
+		t.Errorf("logged out token: expected unauthorized, got %+v", result.Errors)
+	}
+	refresh.Variables["refreshToken"] = refreshed.RefreshToken
+	result = mocktest.QueryResult(t, server, "", refresh)
+	if len(result.Errors) != 1 || result.Errors[0].Extensions.Code != "unauthorized" {
+		t.Errorf("logged out refresh token: expected unauthorized, got %+v", result.Errors)
+	}
+}
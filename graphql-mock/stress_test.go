@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/Bone1289/go-web-example/mock/gql"
+	"github.com/Bone1289/go-web-example/mock/mocktest"
+)
+
+const stressWorkers = 16
+const stressRounds = 10
+
+func testConcurrentArticles(t *testing.T, kind string) {
+	server := newServer(t, kind)
+	tokens := make([]string, stressWorkers)
+	for worker := range tokens {
+		_, tokens[worker] = mocktest.CreateAndLogin(t, server, fmt.Sprintf("stress-%d", worker))
+	}
+
+	var wait sync.WaitGroup
+	for worker := 0; worker < stressWorkers; worker++ {
+		wait.Add(1)
+		go func(worker int) {
+			defer wait.Done()
+			for round := 0; round < stressRounds; round++ {
+				mocktest.Query(t, server, tokens[worker], gql.GraphQLPayload{
+					Query: `mutation($article: ArticleInput) { createArticle(article: $article) { id } }`,
+					Variables: map[string]interface{}{
+						"article": map[string]interface{}{"title": "title", "content": "content"},
+					},
+				})
+				mocktest.Query(t, server, "", gql.GraphQLPayload{
+					Query: `{ articles(first: 20) { totalCount edges { cursor node { id title author { id username } } } } }`,
+				})
+			}
+		}(worker)
+	}
+	wait.Wait()
+
+	articles, _ := mock.Articles.All()
+	expected := len(mock.SeedArticles) + stressWorkers*stressRounds
+	if len(articles) != expected {
+		t.Fatalf("expected %d articles, got %d", expected, len(articles))
+	}
+}
+
+func testConcurrentAuthors(t *testing.T, kind string) {
+	server := newServer(t, kind)
+	authors := make([]mock.Author, stressWorkers)
+	tokens := make([]string, stressWorkers)
+	for worker := range authors {
+		authors[worker], tokens[worker] = mocktest.CreateAndLogin(t, server, fmt.Sprintf("author-%d", worker))
+	}
+
+	var wait sync.WaitGroup
+	for index, author := range authors {
+		wait.Add(2)
+		go func(author mock.Author, token string) {
+			defer wait.Done()
+			mocktest.Query(t, server, token, gql.GraphQLPayload{
+				Query: `mutation($author: AuthorInput) { updateAuthor(author: $author) { id } }`,
+				Variables: map[string]interface{}{
+					"author": map[string]interface{}{"id": author.Id, "firstname": "First"},
+				},
+			})
+			mocktest.Query(t, server, "", gql.GraphQLPayload{Query: `{ authors { edges { node { id firstname lastname } } } }`})
+		}(author, tokens[index])
+		go func(index int, author mock.Author, token string) {
+			defer wait.Done()
+			mocktest.Query(t, server, token, gql.GraphQLPayload{
+				Query: `mutation($author: AuthorInput) { updateAuthor(author: $author) { id } }`,
+				Variables: map[string]interface{}{
+					"author": map[string]interface{}{"id": author.Id, "lastname": "Last"},
+				},
+			})
+			if index%2 == 0 {
+				mocktest.Query(t, server, token, gql.GraphQLPayload{
+					Query:     `mutation($id: String!) { deleteAuthor(id: $id) { id } }`,
+					Variables: map[string]interface{}{"id": author.Id},
+				})
+			}
+		}(index, author, tokens[index])
+	}
+	wait.Wait()
+
+	remaining, _ := mock.Authors.All()
+	expected := len(mock.SeedAuthors) + len(authors)/2
+	if len(remaining) != expected {
+		t.Fatalf("expected %d authors, got %d", expected, len(remaining))
+	}
+	for _, author := range remaining[len(mock.SeedAuthors):] {
+		if author.Firstname != "First" || author.Lastname != "Last" {
+			t.Errorf("lost update on author %s: %+v", author.Id, author)
+		}
+	}
+}
+
+func TestConcurrentArticlesMemory(t *testing.T) {
+	testConcurrentArticles(t, "memory")
+}
+
+func TestConcurrentArticlesFile(t *testing.T) {
+	testConcurrentArticles(t, "file")
+}
+
+func TestConcurrentAuthorsMemory(t *testing.T) {
+	testConcurrentAuthors(t, "memory")
+}
+
+func TestConcurrentAuthorsFile(t *testing.T) {
+	testConcurrentAuthors(t, "file")
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/Bone1289/go-web-example/mock/gql"
+	"github.com/Bone1289/go-web-example/mock/mocktest"
+)
+
+func TestQueryToken(t *testing.T) {
+	server := newServer(t, "memory")
+	_, token := mocktest.CreateAndLogin(t, server, "query-token")
+	queryToken := mock.QueryToken
+	t.Cleanup(func() { mock.QueryToken = queryToken })
+
+	tests := []struct {
+		name       string
+		queryToken bool
+		header     string
+		parameter  string
+		code       string
+	}{
+		{"parameter ignored by default", false, "", token, "unauthorized"},
+		{"header without the fallback", false, "Bearer " + token, "", ""},
+		{"parameter with the fallback", true, "", token, ""},
+		{"invalid parameter with the fallback", true, "", "not-a-token", "unauthorized"},
+		{"header over a valid parameter", true, "Bearer not-a-token", token, "unauthorized"},
+		{"malformed header over a valid parameter", true, "Basic " + token, token, "unauthorized"},
+		{"header over an invalid parameter", true, "Bearer " + token, "not-a-token", ""},
+	}
+	for _, test := range tests {
+		mock.QueryToken = test.queryToken
+		var body bytes.Buffer
+		json.NewEncoder(&body).Encode(gql.GraphQLPayload{
+			Query: `mutation { createArticle(article: {title: "title", content: "content"}) { id } }`,
+		})
+		request, _ := http.NewRequest("POST", server.URL+"/graphql?token="+url.QueryEscape(test.parameter), &body)
+		request.Header.Set("content-type", "application/json")
+		if test.header != "" {
+			request.Header.Set("authorization", test.header)
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var result mocktest.GraphQLResult
+		json.NewDecoder(response.Body).Decode(&result)
+		response.Body.Close()
+		code := ""
+		if len(result.Errors) > 0 {
+			code = result.Errors[0].Extensions.Code
+		}
+		if code != test.code || len(result.Errors) > 1 {
+			t.Errorf("%s: expected code %q, got %+v", test.name, test.code, result.Errors)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/Bone1289/go-web-example/mock/gql"
+	"github.com/Bone1289/go-web-example/mock/rest"
+	"github.com/gorilla/mux"
+	"os"
+)
+
+// NewRouter mounts the REST API and /graphql side by side. Both read and write
+// the same mock.Authors and mock.Articles stores, so records created through
+// one API are immediately visible through the other.
+func NewRouter() (*mux.Router, error) {
+	router := mux.NewRouter()
+	err := gql.Register(router)
+	if err != nil {
+		return nil, err
+	}
+	rest.Register(router)
+	mock.RegisterAdmin(router)
+	return router, nil
+}
+
+func main() {
+	config, err := mock.LoadConfig(os.Args[1:])
+	if err == flag.ErrHelp {
+		os.Exit(0)
+	} else if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	err = config.Apply()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	router, err := NewRouter()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fmt.Println("Starting application...")
+	err = mock.Serve(config, mock.CORS(router))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/Bone1289/go-web-example/mock/gql"
+	"github.com/Bone1289/go-web-example/mock/mocktest"
+)
+
+type articleConnection struct {
+	Edges []struct {
+		Node struct {
+			Id     string
+			Title  string
+			Author struct{ Username string }
+		}
+	}
+}
+
+// TestSharedStores writes through each API and reads the record back through
+// the other one.
+func TestSharedStores(t *testing.T) {
+	for _, kind := range []string{"memory", "file"} {
+		t.Run(kind, func(t *testing.T) {
+			router, err := NewRouter()
+			if err != nil {
+				t.Fatal(err)
+			}
+			server := mocktest.NewServer(t, kind, router)
+			_, token := mocktest.CreateAndLogin(t, server, "shared")
+
+			var created mock.Article
+			status := mocktest.Call(t, "POST", server.URL+"/article", token, mock.Article{Title: "from rest", Content: "content"}, &created)
+			if status != http.StatusCreated || created.Id == "" {
+				t.Fatalf("POST /article: status %d, article %+v", status, created)
+			}
+			var connection articleConnection
+			result := mocktest.Query(t, server, "", gql.GraphQLPayload{
+				Query: `{ articles { edges { node { id title author { username } } } } }`,
+			})
+			json.Unmarshal(result.Data["articles"], &connection)
+			found := false
+			for _, edge := range connection.Edges {
+				if edge.Node.Id == created.Id {
+					found = true
+					if edge.Node.Title != "from rest" || edge.Node.Author.Username != "shared" {
+						t.Errorf("GraphQL articles: unexpected article %+v", edge.Node)
+					}
+				}
+			}
+			if !found {
+				t.Errorf("GraphQL articles: REST article %s missing from %s", created.Id, result.Data["articles"])
+			}
+
+			var articles []mock.Article
+			result = mocktest.Query(t, server, token, gql.GraphQLPayload{
+				Query: `mutation { createArticle(article: {title: "from graphql", content: "content"}) { id title } }`,
+			})
+			json.Unmarshal(result.Data["createArticle"], &articles)
+			id := ""
+			for _, article := range articles {
+				if article.Title == "from graphql" {
+					id = article.Id
+				}
+			}
+			if id == "" {
+				t.Fatalf("createArticle returned %s", result.Data["createArticle"])
+			}
+			var article mock.Article
+			if status := mocktest.Call(t, "GET", server.URL+"/article/"+id, "", nil, &article); status != http.StatusOK || article.Title != "from graphql" {
+				t.Errorf("GET /article/%s: status %d, article %+v", id, status, article)
+			}
+			articles = nil
+			mocktest.Call(t, "GET", server.URL+"/articles?id="+id, "", nil, &articles)
+			if len(articles) != 1 {
+				t.Errorf("GET /articles: expected the GraphQL article, got %+v", articles)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package mock
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// AdminKey must be sent in the X-Admin-Key header of every /admin request.
+// The admin endpoints are disabled while it is empty.
+var AdminKey string
+
+// snapshots live for the lifetime of the process, independent of the store
+// backend, so restoring one never touches the persisted fixture files.
+var snapshots = map[string]Fixture{}
+var snapshotsMutex sync.RWMutex
+
+func AdminMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(response http.ResponseWriter, request *http.Request) {
+		if AdminKey == "" {
+			WriteError(response, NewError(http.StatusForbidden, "admin_disabled", "admin endpoints are disabled"))
+			return
+		}
+		key := request.Header.Get("X-Admin-Key")
+		if subtle.ConstantTimeCompare([]byte(key), []byte(AdminKey)) != 1 {
+			WriteError(response, NewError(http.StatusUnauthorized, "invalid_admin_key", "invalid admin key"))
+			return
+		}
+		next(response, request)
+	}
+}
+
+// ResetEndpoint wipes the stores back to the seed fixture. A test suite may
+// instead post its own fixture document to start from a different dataset.
+func ResetEndpoint(response http.ResponseWriter, request *http.Request) {
+	response.Header().Add("content-type", "application/json")
+	fixture := Fixture{Authors: SeedAuthors, Articles: SeedArticles}
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		WriteError(response, BadRequest(err.Error()))
+		return
+	}
+	if len(bytes.TrimSpace(body)) > 0 {
+		fixture = Fixture{}
+		err = json.Unmarshal(body, &fixture)
+		if err != nil {
+			WriteError(response, BadRequest("invalid JSON body: "+err.Error()))
+			return
+		}
+		err = fixture.Prepare()
+		if err != nil {
+			WriteError(response, BadRequest(err.Error()))
+			return
+		}
+	}
+	err = Reset(fixture)
+	if err != nil {
+		WriteError(response, err)
+		return
+	}
+	json.NewEncoder(response).Encode(map[string]int{
+		"authors":  len(fixture.Authors),
+		"articles": len(fixture.Articles),
+	})
+}
+
+// Snapshot copies the current contents of both stores.
+func Snapshot() (Fixture, error) {
+	dataset.Lock()
+	defer dataset.Unlock()
+	authors, err := Authors.All()
+	if err != nil {
+		return Fixture{}, err
+	}
+	articles, err := Articles.All()
+	if err != nil {
+		return Fixture{}, err
+	}
+	return Fixture{Authors: authors, Articles: articles}, nil
+}
+
+func SnapshotListEndpoint(response http.ResponseWriter, request *http.Request) {
+	response.Header().Add("content-type", "application/json")
+	snapshotsMutex.RLock()
+	names := []string{}
+	for name := range snapshots {
+		names = append(names, name)
+	}
+	snapshotsMutex.RUnlock()
+	sort.Strings(names)
+	json.NewEncoder(response).Encode(names)
+}
+
+func SnapshotCreateEndpoint(response http.ResponseWriter, request *http.Request) {
+	response.Header().Add("content-type", "application/json")
+	params := mux.Vars(request)
+	fixture, err := Snapshot()
+	if err != nil {
+		WriteError(response, err)
+		return
+	}
+	snapshotsMutex.Lock()
+	snapshots[params["name"]] = fixture
+	snapshotsMutex.Unlock()
+	response.WriteHeader(http.StatusCreated)
+	json.NewEncoder(response).Encode(map[string]interface{}{
+		"name":     params["name"],
+		"authors":  len(fixture.Authors),
+		"articles": len(fixture.Articles),
+	})
+}
+
+func SnapshotRestoreEndpoint(response http.ResponseWriter, request *http.Request) {
+	response.Header().Add("content-type", "application/json")
+	params := mux.Vars(request)
+	snapshotsMutex.RLock()
+	fixture, ok := snapshots[params["name"]]
+	snapshotsMutex.RUnlock()
+	if !ok {
+		WriteError(response, NewError(http.StatusNotFound, "not_found", "snapshot "+params["name"]+" not found"))
+		return
+	}
+	err := Reset(fixture)
+	if err != nil {
+		WriteError(response, err)
+		return
+	}
+	json.NewEncoder(response).Encode(map[string]interface{}{
+		"name":     params["name"],
+		"authors":  len(fixture.Authors),
+		"articles": len(fixture.Articles),
+	})
+}
+
+func SnapshotDeleteEndpoint(response http.ResponseWriter, request *http.Request) {
+	params := mux.Vars(request)
+	snapshotsMutex.Lock()
+	_, ok := snapshots[params["name"]]
+	delete(snapshots, params["name"])
+	snapshotsMutex.Unlock()
+	if !ok {
+		WriteError(response, NewError(http.StatusNotFound, "not_found", "snapshot "+params["name"]+" not found"))
+		return
+	}
+	response.WriteHeader(http.StatusNoContent)
+}
+
+// RegisterAdmin mounts the test-harness endpoints under /admin, all guarded
+// by AdminMiddleware.
+func RegisterAdmin(router *mux.Router) {
+	router.HandleFunc("/admin/reset", AdminMiddleware(ResetEndpoint)).Methods("POST")
+	router.HandleFunc("/admin/snapshots", AdminMiddleware(SnapshotListEndpoint)).Methods("GET")
+	router.HandleFunc("/admin/snapshots/{name}", AdminMiddleware(SnapshotCreateEndpoint)).Methods("POST")
+	router.HandleFunc("/admin/snapshots/{name}", AdminMiddleware(SnapshotDeleteEndpoint)).Methods("DELETE")
+	router.HandleFunc("/admin/snapshots/{name}/restore", AdminMiddleware(SnapshotRestoreEndpoint)).Methods("POST")
+}
@@ -0,0 +1,108 @@
+package mock
+
+import (
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+type Article struct {
+	Id        string    `json:"id,omitempty" yaml:"id,omitempty" validate:"omitempty,uuid"`
+	Author    string    `json:"author,omitempty" yaml:"author,omitempty" validate:"omitempty"`
+	Title     string    `json:"title,omitempty" yaml:"title,omitempty" validate:"required"`
+	Content   string    `json:"content,omitempty" yaml:"content,omitempty" validate:"required"`
+	CreatedAt time.Time `json:"createdAt" yaml:"createdAt"`
+}
+
+// CreateArticle stores article as written by actor.
+func CreateArticle(actor CustomJWTClaims, article Article) (Article, error) {
+	err := Require(actor, PermCreateArticle)
+	if err != nil {
+		return Article{}, err
+	}
+	validate := newValidator()
+	err = validate.Struct(article)
+	if err != nil {
+		return Article{}, err
+	}
+	article.Id = uuid.Must(uuid.NewV4()).String()
+	article.Author = actor.Id
+	article.CreatedAt = time.Now().UTC()
+	dataset.RLock()
+	defer dataset.RUnlock()
+	return article, Articles.Create(article)
+}
+
+// ArticleFields lists the fields articles can be sorted and filtered on.
+var ArticleFields = []string{"id", "author", "title", "content", "createdAt"}
+
+func articleField(article Article, field string) string {
+	switch field {
+	case "id":
+		return article.Id
+	case "author":
+		return article.Author
+	case "title":
+		return article.Title
+	case "content":
+		return article.Content
+	case "createdAt":
+		return sortableTime(article.CreatedAt)
+	}
+	return ""
+}
+
+func ListArticles(query ListQuery) ([]Article, Page, error) {
+	articles, err := Articles.All()
+	if err != nil {
+		return nil, Page{}, err
+	}
+	indices, page, err := query.Select(len(articles), func(index int, field string) string {
+		return articleField(articles[index], field)
+	})
+	if err != nil {
+		return nil, Page{}, err
+	}
+	selected := make([]Article, len(indices))
+	for position, index := range indices {
+		selected[position] = articles[index]
+	}
+	return selected, page, nil
+}
+
+// UpdateArticle applies the non-empty fields of changes to an article on
+// behalf of actor. Changing someone else's article needs PermUpdateAnyArticle.
+func UpdateArticle(id string, actor CustomJWTClaims, changes Article) (Article, error) {
+	validate := newValidator()
+	err := validate.StructExcept(changes, "Title", "Content")
+	if err != nil {
+		return Article{}, err
+	}
+	dataset.RLock()
+	defer dataset.RUnlock()
+	return Articles.Update(id, func(article *Article) error {
+		if err := Authorize(actor, PermUpdateOwnArticle, PermUpdateAnyArticle, article.Author); err != nil {
+			return err
+		}
+		if changes.Title != "" {
+			article.Title = changes.Title
+		}
+		if changes.Content != "" {
+			article.Content = changes.Content
+		}
+		return nil
+	})
+}
+
+func DeleteArticle(id string, actor CustomJWTClaims) error {
+	dataset.RLock()
+	defer dataset.RUnlock()
+	article, err := Articles.Get(id)
+	if err != nil {
+		return err
+	}
+	err = Authorize(actor, PermDeleteOwnArticle, PermDeleteAnyArticle, article.Author)
+	if err != nil {
+		return err
+	}
+	return Articles.Delete(id)
+}
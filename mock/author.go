@@ -0,0 +1,229 @@
+package mock
+
+import (
+	"encoding/json"
+	"errors"
+	uuid "github.com/satori/go.uuid"
+	"golang.org/x/crypto/bcrypt"
+	"net/http"
+	"time"
+)
+
+type Author struct {
+	Id        string    `json:"id,omitempty" yaml:"id,omitempty" validate:"omitempty,uuid"`
+	Firstname string    `json:"firstname,omitempty" yaml:"firstname,omitempty" validate:"required"`
+	Lastname  string    `json:"lastname,omitempty" yaml:"lastname,omitempty" validate:"required"`
+	Username  string    `json:"username,omitempty" yaml:"username,omitempty" validate:"required"`
+	Password  string    `json:"password,omitempty" yaml:"password,omitempty" validate:"required,gte=4"`
+	Role      string    `json:"role,omitempty" yaml:"role,omitempty" validate:"omitempty,oneof=admin editor author reader"`
+	CreatedAt time.Time `json:"createdAt" yaml:"createdAt"`
+}
+
+// PublicAuthor is the representation of an author returned to clients. It
+// has no password field, so a hash can never be encoded by mistake; the
+// password on Author is only ever read from requests.
+type PublicAuthor struct {
+	Id        string    `json:"id"`
+	Firstname string    `json:"firstname"`
+	Lastname  string    `json:"lastname"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (author Author) Public() PublicAuthor {
+	return PublicAuthor{
+		Id:        author.Id,
+		Firstname: author.Firstname,
+		Lastname:  author.Lastname,
+		Username:  author.Username,
+		Role:      author.Role,
+		CreatedAt: author.CreatedAt,
+	}
+}
+
+func PublicAuthors(authors []Author) []PublicAuthor {
+	public := make([]PublicAuthor, len(authors))
+	for index, author := range authors {
+		public[index] = author.Public()
+	}
+	return public
+}
+
+var BcryptCost = 10
+
+var ErrInvalidUsername = errors.New("invalid username")
+var ErrInvalidPassword = errors.New("invalid password")
+
+// RegisterAuthor stores a new author with RoleAuthor, whatever role was asked
+// for.
+func RegisterAuthor(author Author) (Author, error) {
+	author.Role = RoleAuthor
+	validate := newValidator()
+	err := validate.Struct(author)
+	if err != nil {
+		return Author{}, err
+	}
+	hash, _ := bcrypt.GenerateFromPassword([]byte(author.Password), BcryptCost)
+	author.Id = uuid.Must(uuid.NewV4()).String()
+	author.Password = string(hash)
+	author.CreatedAt = time.Now().UTC()
+	dataset.RLock()
+	defer dataset.RUnlock()
+	return author, Authors.Create(author)
+}
+
+// AuthorFields lists the fields authors can be sorted and filtered on.
+var AuthorFields = []string{"id", "firstname", "lastname", "username", "role", "createdAt"}
+
+func authorField(author Author, field string) string {
+	switch field {
+	case "id":
+		return author.Id
+	case "firstname":
+		return author.Firstname
+	case "lastname":
+		return author.Lastname
+	case "username":
+		return author.Username
+	case "role":
+		return author.Role
+	case "createdAt":
+		return sortableTime(author.CreatedAt)
+	}
+	return ""
+}
+
+func ListAuthors(query ListQuery) ([]Author, Page, error) {
+	authors, err := Authors.All()
+	if err != nil {
+		return nil, Page{}, err
+	}
+	indices, page, err := query.Select(len(authors), func(index int, field string) string {
+		return authorField(authors[index], field)
+	})
+	if err != nil {
+		return nil, Page{}, err
+	}
+	selected := make([]Author, len(indices))
+	for position, index := range indices {
+		selected[position] = authors[index]
+	}
+	return selected, page, nil
+}
+
+// VerifyCredentials returns the author matching the username and password of
+// credentials.
+func VerifyCredentials(credentials Author) (Author, error) {
+	validate := newValidator()
+	err := validate.StructExcept(credentials, "Firstname", "Lastname")
+	if err != nil {
+		return Author{}, err
+	}
+	author, err := Authors.GetByUsername(credentials.Username)
+	if err == ErrNotFound {
+		return Author{}, ErrInvalidUsername
+	} else if err != nil {
+		return Author{}, err
+	}
+	err = bcrypt.CompareHashAndPassword([]byte(author.Password), []byte(credentials.Password))
+	if err != nil {
+		return Author{}, ErrInvalidPassword
+	}
+	return author, nil
+}
+
+// UpdateAuthor applies the non-empty fields of changes to the author with the
+// given id on behalf of actor, hashing a new password before it reaches the
+// store. Changing the role additionally needs PermAssignRoles.
+func UpdateAuthor(id string, actor CustomJWTClaims, changes Author) (Author, error) {
+	err := Authorize(actor, PermUpdateOwnAuthor, PermUpdateAnyAuthor, id)
+	if err == nil && changes.Role != "" {
+		err = Require(actor, PermAssignRoles)
+	}
+	if err != nil {
+		return Author{}, err
+	}
+	validate := newValidator()
+	err = validate.StructExcept(changes, "Id", "Firstname", "Lastname", "Username", "Password")
+	if err != nil {
+		return Author{}, err
+	}
+	if changes.Password != "" {
+		err = validate.StructPartial(changes, "Password")
+		if err != nil {
+			return Author{}, err
+		}
+		hash, _ := bcrypt.GenerateFromPassword([]byte(changes.Password), BcryptCost)
+		changes.Password = string(hash)
+	}
+	dataset.RLock()
+	defer dataset.RUnlock()
+	return Authors.Update(id, func(author *Author) error {
+		if changes.Firstname != "" {
+			author.Firstname = changes.Firstname
+		}
+		if changes.Lastname != "" {
+			author.Lastname = changes.Lastname
+		}
+		if changes.Username != "" {
+			author.Username = changes.Username
+		}
+		if changes.Password != "" {
+			author.Password = changes.Password
+		}
+		if changes.Role != "" {
+			author.Role = changes.Role
+		}
+		return nil
+	})
+}
+
+func DeleteAuthor(id string, actor CustomJWTClaims) error {
+	err := Authorize(actor, PermDeleteOwnAuthor, PermDeleteAnyAuthor, id)
+	if err != nil {
+		return err
+	}
+	dataset.RLock()
+	defer dataset.RUnlock()
+	return Authors.Delete(id)
+}
+
+func RegisterEndpoint(response http.ResponseWriter, request *http.Request) {
+	response.Header().Add("content-type", "application/json")
+	var author Author
+	err := DecodeBody(request, &author)
+	if err != nil {
+		WriteError(response, err)
+		return
+	}
+	_, err = RegisterAuthor(author)
+	if err != nil {
+		WriteError(response, err)
+		return
+	}
+	authors, _ := Authors.All()
+	response.WriteHeader(http.StatusCreated)
+	json.NewEncoder(response).Encode(PublicAuthors(authors))
+}
+
+func LoginEndpoint(response http.ResponseWriter, request *http.Request) {
+	response.Header().Add("content-type", "application/json")
+	var data Author
+	err := DecodeBody(request, &data)
+	if err != nil {
+		WriteError(response, err)
+		return
+	}
+	author, err := VerifyCredentials(data)
+	if err != nil {
+		WriteError(response, err)
+		return
+	}
+	tokens, err := NewTokens(author)
+	if err != nil {
+		WriteError(response, err)
+		return
+	}
+	json.NewEncoder(response).Encode(tokens)
+}
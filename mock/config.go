@@ -0,0 +1,365 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds every setting a mock binary needs at startup. Values are
+// layered in this order, later sources winning: defaults, the optional
+// config file, MOCK_* environment variables and finally command line flags.
+type Config struct {
+	Addr                 string   `json:"addr" yaml:"addr"`
+	Store                string   `json:"store" yaml:"store"`
+	DataDir              string   `json:"dataDir" yaml:"dataDir"`
+	Seed                 string   `json:"seed" yaml:"seed"`
+	JwtSecret            string   `json:"jwtSecret" yaml:"jwtSecret"`
+	JwtIssuer            string   `json:"jwtIssuer" yaml:"jwtIssuer"`
+	JwtAudience          string   `json:"jwtAudience" yaml:"jwtAudience"`
+	SigningKey           string   `json:"signingKey" yaml:"signingKey"`
+	VerificationKeys     []string `json:"verificationKeys" yaml:"verificationKeys"`
+	TokenLifetime        Duration `json:"tokenLifetime" yaml:"tokenLifetime"`
+	RefreshTokenLifetime Duration `json:"refreshTokenLifetime" yaml:"refreshTokenLifetime"`
+	ClockSkew            Duration `json:"clockSkew" yaml:"clockSkew"`
+	BcryptCost           int      `json:"bcryptCost" yaml:"bcryptCost"`
+	CORSOrigins          []string `json:"corsOrigins" yaml:"corsOrigins"`
+	AdminKey             string   `json:"adminKey" yaml:"adminKey"`
+	QueryToken           bool     `json:"queryToken" yaml:"queryToken"`
+
+	ReadTimeout     Duration `json:"readTimeout" yaml:"readTimeout"`
+	WriteTimeout    Duration `json:"writeTimeout" yaml:"writeTimeout"`
+	IdleTimeout     Duration `json:"idleTimeout" yaml:"idleTimeout"`
+	ShutdownTimeout Duration `json:"shutdownTimeout" yaml:"shutdownTimeout"`
+	FlushOnShutdown bool     `json:"flushOnShutdown" yaml:"flushOnShutdown"`
+}
+
+// Duration accepts Go duration strings such as "90m" in config files.
+type Duration struct {
+	time.Duration
+}
+
+func (duration *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	return duration.Set(value)
+}
+
+func (duration *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var value string
+	if err := unmarshal(&value); err != nil {
+		return err
+	}
+	return duration.Set(value)
+}
+
+func (duration *Duration) Set(value string) error {
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	duration.Duration = parsed
+	return nil
+}
+
+// stringList is a comma separated flag value.
+type stringList []string
+
+func (list *stringList) String() string {
+	return strings.Join(*list, ",")
+}
+
+func (list *stringList) Set(value string) error {
+	*list = splitList(value)
+	return nil
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Addr:                 ":12345",
+		Store:                "memory",
+		DataDir:              "data",
+		JwtSecret:            "thepolyglotdeveloper",
+		JwtIssuer:            "The Polyglot Developer",
+		JwtAudience:          "go-web-example",
+		TokenLifetime:        Duration{time.Hour},
+		RefreshTokenLifetime: Duration{30 * 24 * time.Hour},
+		ClockSkew:            Duration{30 * time.Second},
+		BcryptCost:           10,
+		CORSOrigins:          []string{"*"},
+
+		ReadTimeout:     Duration{15 * time.Second},
+		WriteTimeout:    Duration{15 * time.Second},
+		IdleTimeout:     Duration{time.Minute},
+		ShutdownTimeout: Duration{10 * time.Second},
+		FlushOnShutdown: true,
+	}
+}
+
+// LoadConfig builds a validated Config from the config file, environment and
+// the given command line arguments, usually os.Args[1:].
+func LoadConfig(args []string) (Config, error) {
+	// The first pass only discovers -config; the flags are parsed again below
+	// so that they take precedence over the file and the environment.
+	discovery := DefaultConfig()
+	flags := configFlags(&discovery)
+	flags.SetOutput(ioutil.Discard)
+	configPath := flags.String("config", os.Getenv("MOCK_CONFIG"), "")
+	if err := flags.Parse(args); err != nil && err != flag.ErrHelp {
+		return Config{}, err
+	}
+
+	config := DefaultConfig()
+	if *configPath != "" {
+		if err := decodeFile(*configPath, &config); err != nil {
+			return Config{}, fmt.Errorf("config file %s: %v", *configPath, err)
+		}
+	}
+	if err := config.loadEnv(); err != nil {
+		return Config{}, err
+	}
+	flags = configFlags(&config)
+	flags.String("config", *configPath, "optional JSON or YAML config file (env MOCK_CONFIG)")
+	if err := flags.Parse(args); err != nil {
+		return Config{}, err
+	}
+	return config, config.Validate()
+}
+
+func configFlags(config *Config) *flag.FlagSet {
+	flags := flag.NewFlagSet(filepath.Base(os.Args[0]), flag.ContinueOnError)
+	flags.StringVar(&config.Addr, "addr", config.Addr, "listen address (env MOCK_ADDR)")
+	flags.StringVar(&config.Store, "store", config.Store, "storage backend: memory or file, which also keeps logged out tokens revoked across restarts (env MOCK_STORE)")
+	flags.StringVar(&config.DataDir, "data", config.DataDir, "directory used by the file storage backend (env MOCK_DATA_DIR)")
+	flags.StringVar(&config.Seed, "seed", config.Seed, "JSON or YAML fixture file or directory to seed from (env MOCK_SEED)")
+	flags.StringVar(&config.JwtSecret, "jwt-secret", config.JwtSecret, "HMAC secret used to sign tokens (env MOCK_JWT_SECRET)")
+	flags.StringVar(&config.JwtIssuer, "jwt-issuer", config.JwtIssuer, "issuer claim of issued tokens, required of accepted ones (env MOCK_JWT_ISSUER)")
+	flags.StringVar(&config.JwtAudience, "jwt-audience", config.JwtAudience, "audience claim of issued tokens, required of accepted ones (env MOCK_JWT_AUDIENCE)")
+	flags.StringVar(&config.SigningKey, "signing-key", config.SigningKey, "PEM RSA, ECDSA or Ed25519 private key to sign tokens with instead of the jwt secret (env MOCK_SIGNING_KEY)")
+	flags.Var((*stringList)(&config.VerificationKeys), "verification-keys", "comma separated PEM keys of retired signing keys still accepted (env MOCK_VERIFICATION_KEYS)")
+	flags.DurationVar(&config.TokenLifetime.Duration, "token-lifetime", config.TokenLifetime.Duration, "lifetime of issued tokens (env MOCK_TOKEN_LIFETIME)")
+	flags.DurationVar(&config.RefreshTokenLifetime.Duration, "refresh-token-lifetime", config.RefreshTokenLifetime.Duration, "lifetime of refresh tokens (env MOCK_REFRESH_TOKEN_LIFETIME)")
+	flags.DurationVar(&config.ClockSkew.Duration, "clock-skew", config.ClockSkew.Duration, "leeway allowed when checking the exp, nbf and iat claims (env MOCK_CLOCK_SKEW)")
+	flags.IntVar(&config.BcryptCost, "bcrypt-cost", config.BcryptCost, "bcrypt cost for password hashes (env MOCK_BCRYPT_COST)")
+	flags.Var((*stringList)(&config.CORSOrigins), "cors-origins", "comma separated allowed CORS origins (env MOCK_CORS_ORIGINS)")
+	flags.StringVar(&config.AdminKey, "admin-key", config.AdminKey, "key required by the /admin endpoints, which are disabled when empty (env MOCK_ADMIN_KEY)")
+	flags.BoolVar(&config.QueryToken, "query-token", config.QueryToken, "also accept the legacy ?token= parameter on /graphql (env MOCK_QUERY_TOKEN)")
+	flags.DurationVar(&config.ReadTimeout.Duration, "read-timeout", config.ReadTimeout.Duration, "maximum time to read a request, 0 for none (env MOCK_READ_TIMEOUT)")
+	flags.DurationVar(&config.WriteTimeout.Duration, "write-timeout", config.WriteTimeout.Duration, "maximum time to write a response, 0 for none (env MOCK_WRITE_TIMEOUT)")
+	flags.DurationVar(&config.IdleTimeout.Duration, "idle-timeout", config.IdleTimeout.Duration, "keep-alive idle timeout, 0 for none (env MOCK_IDLE_TIMEOUT)")
+	flags.DurationVar(&config.ShutdownTimeout.Duration, "shutdown-timeout", config.ShutdownTimeout.Duration, "time allowed to drain connections on shutdown (env MOCK_SHUTDOWN_TIMEOUT)")
+	flags.BoolVar(&config.FlushOnShutdown, "flush-on-shutdown", config.FlushOnShutdown, "flush persistent stores after draining (env MOCK_FLUSH_ON_SHUTDOWN)")
+	return flags
+}
+
+// decodeFile strictly decodes a JSON or YAML document, picking the format from
+// the file extension.
+func decodeFile(path string, value interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return yaml.UnmarshalStrict(data, value)
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(value); err != nil {
+			return err
+		}
+		if decoder.More() {
+			return errors.New("unexpected data after the JSON document")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported format %q", filepath.Ext(path))
+}
+
+func (config *Config) loadEnv() error {
+	if value, ok := os.LookupEnv("MOCK_ADDR"); ok {
+		config.Addr = value
+	}
+	if value, ok := os.LookupEnv("MOCK_STORE"); ok {
+		config.Store = value
+	}
+	if value, ok := os.LookupEnv("MOCK_DATA_DIR"); ok {
+		config.DataDir = value
+	}
+	if value, ok := os.LookupEnv("MOCK_SEED"); ok {
+		config.Seed = value
+	}
+	if value, ok := os.LookupEnv("MOCK_JWT_SECRET"); ok {
+		config.JwtSecret = value
+	}
+	if value, ok := os.LookupEnv("MOCK_JWT_ISSUER"); ok {
+		config.JwtIssuer = value
+	}
+	if value, ok := os.LookupEnv("MOCK_JWT_AUDIENCE"); ok {
+		config.JwtAudience = value
+	}
+	if value, ok := os.LookupEnv("MOCK_SIGNING_KEY"); ok {
+		config.SigningKey = value
+	}
+	if value, ok := os.LookupEnv("MOCK_VERIFICATION_KEYS"); ok {
+		config.VerificationKeys = splitList(value)
+	}
+	durations := map[string]*Duration{
+		"MOCK_TOKEN_LIFETIME":         &config.TokenLifetime,
+		"MOCK_REFRESH_TOKEN_LIFETIME": &config.RefreshTokenLifetime,
+		"MOCK_CLOCK_SKEW":             &config.ClockSkew,
+		"MOCK_READ_TIMEOUT":           &config.ReadTimeout,
+		"MOCK_WRITE_TIMEOUT":          &config.WriteTimeout,
+		"MOCK_IDLE_TIMEOUT":           &config.IdleTimeout,
+		"MOCK_SHUTDOWN_TIMEOUT":       &config.ShutdownTimeout,
+	}
+	for name, duration := range durations {
+		if value, ok := os.LookupEnv(name); ok {
+			if err := duration.Set(value); err != nil {
+				return fmt.Errorf("%s: %v", name, err)
+			}
+		}
+	}
+	if value, ok := os.LookupEnv("MOCK_BCRYPT_COST"); ok {
+		cost, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("MOCK_BCRYPT_COST: %v", err)
+		}
+		config.BcryptCost = cost
+	}
+	if value, ok := os.LookupEnv("MOCK_CORS_ORIGINS"); ok {
+		config.CORSOrigins = splitList(value)
+	}
+	if value, ok := os.LookupEnv("MOCK_ADMIN_KEY"); ok {
+		config.AdminKey = value
+	}
+	if value, ok := os.LookupEnv("MOCK_QUERY_TOKEN"); ok {
+		allow, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("MOCK_QUERY_TOKEN: %v", err)
+		}
+		config.QueryToken = allow
+	}
+	if value, ok := os.LookupEnv("MOCK_FLUSH_ON_SHUTDOWN"); ok {
+		flush, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("MOCK_FLUSH_ON_SHUTDOWN: %v", err)
+		}
+		config.FlushOnShutdown = flush
+	}
+	return nil
+}
+
+func (config Config) Validate() error {
+	var problems []string
+	if config.Addr == "" {
+		problems = append(problems, "addr must not be empty")
+	}
+	if config.Store != "memory" && config.Store != "file" {
+		problems = append(problems, fmt.Sprintf("store must be memory or file, got %q", config.Store))
+	}
+	if config.Store == "file" && config.DataDir == "" {
+		problems = append(problems, "data directory is required by the file store")
+	}
+	if config.JwtSecret == "" {
+		problems = append(problems, "jwt secret must not be empty")
+	}
+	if config.JwtIssuer == "" || config.JwtAudience == "" {
+		problems = append(problems, "jwt issuer and audience must not be empty")
+	}
+	if config.SigningKey == "" && len(config.VerificationKeys) > 0 {
+		problems = append(problems, "verification keys require a signing key")
+	}
+	if config.TokenLifetime.Duration <= 0 || config.RefreshTokenLifetime.Duration <= 0 {
+		problems = append(problems, "token lifetimes must be positive")
+	}
+	if config.ClockSkew.Duration < 0 {
+		problems = append(problems, "clock skew must not be negative")
+	}
+	if config.BcryptCost < bcrypt.MinCost || config.BcryptCost > bcrypt.MaxCost {
+		problems = append(problems, fmt.Sprintf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost))
+	}
+	if config.AdminKey != "" && config.AdminKey == config.JwtSecret {
+		problems = append(problems, "admin key must differ from the jwt secret")
+	}
+	if len(config.CORSOrigins) == 0 {
+		problems = append(problems, "at least one CORS origin is required")
+	}
+	if config.ReadTimeout.Duration < 0 || config.WriteTimeout.Duration < 0 || config.IdleTimeout.Duration < 0 {
+		problems = append(problems, "timeouts must not be negative")
+	}
+	if config.ShutdownTimeout.Duration <= 0 {
+		problems = append(problems, "shutdown timeout must be positive")
+	}
+	if len(problems) > 0 {
+		return errors.New("invalid config: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Apply installs the config into the package settings used by the endpoints,
+// loads the seed fixture and opens the configured stores.
+func (config Config) Apply() error {
+	JwtSecret = []byte(config.JwtSecret)
+	JwtIssuer = config.JwtIssuer
+	JwtAudience = config.JwtAudience
+	SigningKey, VerificationKeys = nil, nil
+	if config.SigningKey != "" {
+		key, err := LoadKey(config.SigningKey)
+		if err != nil {
+			return fmt.Errorf("signing key %s: %v", config.SigningKey, err)
+		}
+		if key.Private == nil {
+			return fmt.Errorf("signing key %s: not a private key", config.SigningKey)
+		}
+		SigningKey = key
+		VerificationKeys = append(VerificationKeys, key)
+	}
+	for _, path := range config.VerificationKeys {
+		key, err := LoadKey(path)
+		if err != nil {
+			return fmt.Errorf("verification key %s: %v", path, err)
+		}
+		VerificationKeys = append(VerificationKeys, key)
+	}
+	TokenLifetime = config.TokenLifetime.Duration
+	RefreshTokenLifetime = config.RefreshTokenLifetime.Duration
+	ClockSkew = config.ClockSkew.Duration
+	BcryptCost = config.BcryptCost
+	CORSOrigins = config.CORSOrigins
+	AdminKey = config.AdminKey
+	QueryToken = config.QueryToken
+	if config.Seed != "" {
+		fixture, err := LoadFixture(config.Seed)
+		if err != nil {
+			return fmt.Errorf("seed %s: %v", config.Seed, err)
+		}
+		SeedAuthors = fixture.Authors
+		SeedArticles = fixture.Articles
+	}
+	var err error
+	Authors, Articles, err = OpenStores(config.Store, config.DataDir)
+	return err
+}
+
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
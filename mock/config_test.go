@@ -0,0 +1,183 @@
+package mock_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bone1289/go-web-example/mock"
+)
+
+// clearEnv unsets every MOCK_* variable for the rest of the test, so the
+// environment of the test run cannot leak into the config.
+func clearEnv(t *testing.T) {
+	for _, variable := range os.Environ() {
+		name := strings.SplitN(variable, "=", 2)[0]
+		if strings.HasPrefix(name, "MOCK_") {
+			t.Setenv(name, "")
+			os.Unsetenv(name)
+		}
+	}
+}
+
+func writeConfig(t *testing.T, name string, content string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	yamlFile := writeConfig(t, "config.yaml", "addr: file.yaml:1\ntokenLifetime: 90m\ncorsOrigins: [https://a.example, https://b.example]\n")
+	jsonFile := writeConfig(t, "config.json", `{"addr": "file.json:1", "tokenLifetime": "1h30m", "queryToken": true}`)
+	tests := []struct {
+		name     string
+		env      map[string]string
+		args     []string
+		addr     string
+		lifetime time.Duration
+	}{
+		{"defaults", nil, nil, ":12345", time.Hour},
+		{"yaml file", nil, []string{"-config", yamlFile}, "file.yaml:1", 90 * time.Minute},
+		{"json file", nil, []string{"-config", jsonFile}, "file.json:1", 90 * time.Minute},
+		{"file from the environment", map[string]string{"MOCK_CONFIG": yamlFile}, nil, "file.yaml:1", 90 * time.Minute},
+		{"flag over MOCK_CONFIG", map[string]string{"MOCK_CONFIG": yamlFile}, []string{"-config", jsonFile}, "file.json:1", 90 * time.Minute},
+		{"environment over file", map[string]string{"MOCK_ADDR": "env:1", "MOCK_TOKEN_LIFETIME": "2h"}, []string{"-config", yamlFile}, "env:1", 2 * time.Hour},
+		{"flags over environment", map[string]string{"MOCK_ADDR": "env:1", "MOCK_TOKEN_LIFETIME": "2h"}, []string{"-config", yamlFile, "-addr", "flag:1", "-token-lifetime", "3h"}, "flag:1", 3 * time.Hour},
+		{"flags before -config", nil, []string{"-addr", "flag:1", "-config", yamlFile}, "flag:1", 90 * time.Minute},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clearEnv(t)
+			for name, value := range test.env {
+				t.Setenv(name, value)
+			}
+			config, err := mock.LoadConfig(test.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.Addr != test.addr || config.TokenLifetime.Duration != test.lifetime {
+				t.Errorf("expected %s and %v, got %s and %v", test.addr, test.lifetime, config.Addr, config.TokenLifetime.Duration)
+			}
+		})
+	}
+
+	clearEnv(t)
+	config, err := mock.LoadConfig([]string{"-config", yamlFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(config.CORSOrigins, ",") != "https://a.example,https://b.example" || config.Store != "memory" || config.ClockSkew.Duration != 30*time.Second {
+		t.Errorf("file should only override what it sets: %+v", config)
+	}
+	t.Setenv("MOCK_CORS_ORIGINS", "https://env.example")
+	t.Setenv("MOCK_QUERY_TOKEN", "true")
+	config, err = mock.LoadConfig([]string{"-config", jsonFile, "-cors-origins", "https://c.example,https://d.example", "-query-token=false"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(config.CORSOrigins, ",") != "https://c.example,https://d.example" || config.QueryToken {
+		t.Errorf("flags should win over the file and environment: %+v", config)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		body string
+		env  map[string]string
+		args []string
+	}{
+		{"unknown yaml field", "config.yaml", "address: :1\n", nil, nil},
+		{"unknown json field", "config.json", `{"address": ":1"}`, nil, nil},
+		{"duplicate yaml field", "config.yaml", "addr: :1\naddr: :2\n", nil, nil},
+		{"trailing json", "config.json", `{"addr": ":1"} {`, nil, nil},
+		{"yaml duration", "config.yaml", "clockSkew: soon\n", nil, nil},
+		{"json duration as number", "config.json", `{"clockSkew": 30}`, nil, nil},
+		{"unsupported format", "config.toml", `addr = ":1"`, nil, nil},
+		{"missing file", "", "", nil, []string{"-config", filepath.Join(os.TempDir(), "missing-mock-config.yaml")}},
+		{"environment duration", "", "", map[string]string{"MOCK_CLOCK_SKEW": "soon"}, nil},
+		{"environment bcrypt cost", "", "", map[string]string{"MOCK_BCRYPT_COST": "high"}, nil},
+		{"environment boolean", "", "", map[string]string{"MOCK_QUERY_TOKEN": "maybe"}, nil},
+		{"unknown flag", "", "", nil, []string{"-unknown"}},
+		{"flag duration", "", "", nil, []string{"-clock-skew", "soon"}},
+		{"invalid result", "", "", map[string]string{"MOCK_STORE": "redis"}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clearEnv(t)
+			for name, value := range test.env {
+				t.Setenv(name, value)
+			}
+			args := test.args
+			if test.file != "" {
+				args = append(args, "-config", writeConfig(t, test.file, test.body))
+			}
+			if _, err := mock.LoadConfig(args); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := mock.DefaultConfig().Validate(); err != nil {
+		t.Fatalf("default config: %v", err)
+	}
+	tests := []struct {
+		name     string
+		change   func(config *mock.Config)
+		expected string
+	}{
+		{"empty addr", func(config *mock.Config) { config.Addr = "" }, "addr must not be empty"},
+		{"unknown store", func(config *mock.Config) { config.Store = "redis" }, "store must be memory or file"},
+		{"file store without directory", func(config *mock.Config) { config.Store, config.DataDir = "file", "" }, "data directory is required"},
+		{"empty jwt secret", func(config *mock.Config) { config.JwtSecret = "" }, "jwt secret must not be empty"},
+		{"empty issuer", func(config *mock.Config) { config.JwtIssuer = "" }, "jwt issuer and audience"},
+		{"empty audience", func(config *mock.Config) { config.JwtAudience = "" }, "jwt issuer and audience"},
+		{"verification keys without signing key", func(config *mock.Config) { config.VerificationKeys = []string{"old.pem"} }, "verification keys require a signing key"},
+		{"zero token lifetime", func(config *mock.Config) { config.TokenLifetime.Duration = 0 }, "token lifetimes must be positive"},
+		{"negative refresh lifetime", func(config *mock.Config) { config.RefreshTokenLifetime.Duration = -time.Second }, "token lifetimes must be positive"},
+		{"negative clock skew", func(config *mock.Config) { config.ClockSkew.Duration = -time.Second }, "clock skew must not be negative"},
+		{"bcrypt cost too low", func(config *mock.Config) { config.BcryptCost = 3 }, "bcrypt cost must be between"},
+		{"bcrypt cost too high", func(config *mock.Config) { config.BcryptCost = 32 }, "bcrypt cost must be between"},
+		{"admin key equal to the jwt secret", func(config *mock.Config) { config.AdminKey = config.JwtSecret }, "admin key must differ"},
+		{"no CORS origins", func(config *mock.Config) { config.CORSOrigins = nil }, "at least one CORS origin"},
+		{"negative read timeout", func(config *mock.Config) { config.ReadTimeout.Duration = -time.Second }, "timeouts must not be negative"},
+		{"negative write timeout", func(config *mock.Config) { config.WriteTimeout.Duration = -time.Second }, "timeouts must not be negative"},
+		{"negative idle timeout", func(config *mock.Config) { config.IdleTimeout.Duration = -time.Second }, "timeouts must not be negative"},
+		{"zero shutdown timeout", func(config *mock.Config) { config.ShutdownTimeout.Duration = 0 }, "shutdown timeout must be positive"},
+	}
+	for _, test := range tests {
+		config := mock.DefaultConfig()
+		test.change(&config)
+		err := config.Validate()
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("%s: expected %q, got %v", test.name, test.expected, err)
+		}
+	}
+
+	// Every problem is reported at once.
+	config := mock.DefaultConfig()
+	config.Addr, config.Store = "", "redis"
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "addr") || !strings.Contains(err.Error(), "store") {
+		t.Errorf("expected both problems, got %v", err)
+	}
+	// Timeouts of zero disable them.
+	config = mock.DefaultConfig()
+	config.ReadTimeout.Duration, config.WriteTimeout.Duration, config.IdleTimeout.Duration, config.ClockSkew.Duration = 0, 0, 0, 0
+	if err := config.Validate(); err != nil {
+		t.Errorf("zero timeouts and clock skew: %v", err)
+	}
+}
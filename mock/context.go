@@ -0,0 +1,65 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// QueryToken lets the GraphQL endpoint fall back to the legacy ?token= query
+// parameter, which leaks tokens into logs and browser history.
+var QueryToken = false
+
+type contextKey int
+
+const authenticationKey contextKey = iota
+
+type authentication struct {
+	claims CustomJWTClaims
+	err    error
+}
+
+// BearerToken returns the token of an "Authorization: Bearer" header, or an
+// empty string when the header is absent. Any other scheme or a malformed
+// header is reported as ErrInvalidToken.
+func BearerToken(request *http.Request) (string, error) {
+	header := request.Header.Get("Authorization")
+	if header == "" {
+		return "", nil
+	}
+	// RFC 6750 separates the scheme from the token by a single space, and the
+	// token itself has none.
+	parts := strings.SplitN(header, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") || parts[1] == "" || strings.Contains(parts[1], " ") {
+		return "", ErrInvalidToken
+	}
+	return parts[1], nil
+}
+
+// WriteUnauthorized rejects a request with a 401 and the Bearer challenge of
+// RFC 6750, adding error="invalid_token" when a token was sent but refused.
+func WriteUnauthorized(response http.ResponseWriter, err error) {
+	challenge := `Bearer realm="mock"`
+	if errors.Is(err, ErrInvalidToken) {
+		challenge += `, error="invalid_token"`
+	}
+	response.Header().Set("WWW-Authenticate", challenge)
+	WriteError(response, err)
+}
+
+// WithClaims records the outcome of authenticating a request in ctx, either
+// the claims or the error Authenticate reported.
+func WithClaims(ctx context.Context, claims CustomJWTClaims, err error) context.Context {
+	return context.WithValue(ctx, authenticationKey, authentication{claims: claims, err: err})
+}
+
+// ClaimsFrom returns the claims recorded by WithClaims, or the reason
+// there are none: ErrUnauthorized without a token, ErrInvalidToken otherwise.
+func ClaimsFrom(ctx context.Context) (CustomJWTClaims, error) {
+	auth, ok := ctx.Value(authenticationKey).(authentication)
+	if !ok {
+		return CustomJWTClaims{}, ErrUnauthorized
+	}
+	return auth.claims, auth.err
+}
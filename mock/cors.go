@@ -0,0 +1,35 @@
+package mock
+
+import (
+	"github.com/gorilla/handlers"
+	"net/http"
+)
+
+var CORSOrigins = []string{"*"}
+
+func CORS(handler http.Handler) http.Handler {
+	headers := handlers.AllowedHeaders(
+		[]string{
+			"X-Requested-With",
+			"Content-Type",
+			"Authorization",
+			"X-Admin-Key",
+		},
+	)
+	methods := handlers.AllowedMethods(
+		[]string{
+			"GET",
+			"POST",
+			"PUT",
+			"DELETE",
+		},
+	)
+	exposed := handlers.ExposedHeaders(
+		[]string{
+			"Link",
+			"X-Total-Count",
+		},
+	)
+	origins := handlers.AllowedOrigins(CORSOrigins)
+	return handlers.CORS(headers, methods, exposed, origins)(handler)
+}
@@ -0,0 +1,97 @@
+package mock
+
+import (
+	"encoding/json"
+	"errors"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+)
+
+var ErrConflict = errors.New("record already exists")
+var ErrForbidden = errors.New("not allowed to modify this record")
+var ErrUnauthorized = errors.New("authentication token required")
+var ErrInvalidToken = errors.New("invalid authentication token")
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// Error is the body of every failed API response. Status is only used to pick
+// the HTTP status code and is not part of the JSON document.
+type Error struct {
+	Status  int          `json:"-"`
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Details []FieldError `json:"details,omitempty"`
+}
+
+func (err *Error) Error() string {
+	return err.Message
+}
+
+// Extensions exposes the code and field details to GraphQL clients, which
+// receive them under the "extensions" key of the formatted error.
+func (err *Error) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{"code": err.Code}
+	if len(err.Details) > 0 {
+		extensions["details"] = err.Details
+	}
+	return extensions
+}
+
+func NewError(status int, code string, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+func BadRequest(message string) *Error {
+	return NewError(http.StatusBadRequest, "bad_request", message)
+}
+
+// AsError maps the sentinel errors returned by the stores and services, as
+// well as validator failures, onto an Error with the matching status code.
+// Anything unrecognised becomes a 500.
+func AsError(err error) *Error {
+	var apiError *Error
+	if errors.As(err, &apiError) {
+		return apiError
+	}
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		apiError = NewError(http.StatusUnprocessableEntity, "validation_failed", "validation failed")
+		apiError.Details = fieldErrors(validationErrors)
+		return apiError
+	}
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return NewError(http.StatusNotFound, "not_found", err.Error())
+	case errors.Is(err, ErrConflict):
+		return NewError(http.StatusConflict, "conflict", err.Error())
+	case errors.Is(err, ErrUnauthorized), errors.Is(err, ErrInvalidToken):
+		return NewError(http.StatusUnauthorized, "unauthorized", err.Error())
+	case errors.Is(err, ErrForbidden):
+		return NewError(http.StatusForbidden, "forbidden", err.Error())
+	case errors.Is(err, ErrInvalidUsername), errors.Is(err, ErrInvalidPassword):
+		return NewError(http.StatusUnauthorized, "invalid_credentials", err.Error())
+	}
+	return NewError(http.StatusInternalServerError, "internal_error", err.Error())
+}
+
+func WriteError(response http.ResponseWriter, err error) {
+	apiError := AsError(err)
+	response.Header().Set("content-type", "application/json")
+	response.WriteHeader(apiError.Status)
+	json.NewEncoder(response).Encode(apiError)
+}
+
+// DecodeBody decodes the JSON request body into value, reporting malformed
+// input as a 400.
+func DecodeBody(request *http.Request, value interface{}) error {
+	err := json.NewDecoder(request.Body).Decode(value)
+	if err != nil {
+		return BadRequest("invalid JSON body: " + err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package mock_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Bone1289/go-web-example/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// writeFiles stores files, keyed by name, in a new temporary directory.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+const yamlFixture = `authors:
+  - id: 7d1b4c5e-3f1a-4f53-9d0e-2a7c1f0b6a11
+    firstname: Ada
+    lastname: Lovelace
+    username: ada
+    password: secret
+    role: editor
+    createdAt: 2020-01-02T03:04:05Z
+articles:
+  - id: 0f5e9b2a-8c47-4d1e-a3b6-5c2d7e9f1a34
+    author: 7d1b4c5e-3f1a-4f53-9d0e-2a7c1f0b6a11
+    title: Notes
+    content: On the analytical engine
+    createdAt: 2020-01-03T00:00:00Z
+`
+
+func TestLoadFixture(t *testing.T) {
+	cost := mock.BcryptCost
+	t.Cleanup(func() { mock.BcryptCost = cost })
+	mock.BcryptCost = bcrypt.MinCost
+	hash, _ := bcrypt.GenerateFromPassword([]byte("hashed"), bcrypt.MinCost)
+
+	dir := writeFiles(t, map[string]string{"fixture.yaml": yamlFixture})
+	fixture, err := mock.LoadFixture(filepath.Join(dir, "fixture.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixture.Authors) != 1 || len(fixture.Articles) != 1 {
+		t.Fatalf("unexpected fixture %+v", fixture)
+	}
+	author, article := fixture.Authors[0], fixture.Articles[0]
+	if author.Username != "ada" || author.Role != mock.RoleEditor || !author.CreatedAt.Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("unexpected author %+v", author)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(author.Password), []byte("secret")); err != nil {
+		t.Errorf("plain-text password was not hashed: %v", err)
+	}
+	if article.Author != author.Id || article.Title != "Notes" || !article.CreatedAt.Equal(time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected article %+v", article)
+	}
+
+	// A directory merges its JSON and YAML files in name order and skips
+	// anything else.
+	dir = writeFiles(t, map[string]string{
+		"1-authors.json": `{"authors": [{"id": "first", "username": "first", "password": "` + string(hash) + `"}]}`,
+		"2-more.yml":     "authors:\n  - id: second\n    username: second\n    password: secret\narticles:\n  - id: article\n    author: second\n",
+		"README.txt":     "not a fixture",
+	})
+	fixture, err = mock.LoadFixture(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixture.Authors) != 2 || fixture.Authors[0].Id != "first" || fixture.Authors[1].Id != "second" || len(fixture.Articles) != 1 {
+		t.Fatalf("unexpected directory fixture %+v", fixture)
+	}
+	if fixture.Authors[0].Password != string(hash) {
+		t.Error("a bcrypt hash was hashed again")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(fixture.Authors[1].Password), []byte("secret")); err != nil {
+		t.Errorf("plain-text password was not hashed: %v", err)
+	}
+}
+
+func TestLoadFixtureErrors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"unknown.yaml":   "authors:\n  - id: a\n    username: a\n    created: 2020-01-01T00:00:00Z\n",
+		"unknown.json":   `{"authors": [{"id": "a", "username": "a", "created": "2020-01-01T00:00:00Z"}]}`,
+		"duplicate.yaml": "authors:\n  - id: a\n    username: a\n  - id: a\n    username: b\n",
+		"role.yaml":      "authors:\n  - id: a\n    username: a\n    role: owner\n",
+		"id.yaml":        "articles:\n  - title: no id\n",
+		"format.toml":    "",
+	})
+	for _, name := range []string{"unknown.yaml", "unknown.json", "duplicate.yaml", "role.yaml", "id.yaml", "format.toml", "missing.yaml"} {
+		if _, err := mock.LoadFixture(filepath.Join(dir, name)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
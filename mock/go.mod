@@ -0,0 +1,21 @@
+module github.com/Bone1289/go-web-example/mock
+
+go 1.18
+
+require (
+	github.com/golang-jwt/jwt/v5 v5.2.3
+	github.com/gorilla/handlers v1.4.2
+	github.com/gorilla/mux v1.7.4
+	github.com/graphql-go/graphql v0.7.9
+	github.com/mitchellh/mapstructure v1.3.3
+	github.com/satori/go.uuid v1.2.1-0.20181028125025-b2ce2384e17b
+	golang.org/x/crypto v0.0.0-20200728195943-123391ffb6de
+	gopkg.in/go-playground/validator.v9 v9.31.0
+	gopkg.in/yaml.v2 v2.4.0
+)
+
+require (
+	github.com/go-playground/locales v0.13.0 // indirect
+	github.com/go-playground/universal-translator v0.17.0 // indirect
+	github.com/leodido/go-urn v1.2.0 // indirect
+)
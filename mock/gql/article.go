@@ -1,13 +1,9 @@
-package main
+package gql
 
-import "github.com/graphql-go/graphql"
-
-type Article struct {
-	Id      string `json:"id,omitempty" validate:"omitempty,uuid"`
-	Author  string `json:"author,omitempty" validate:"isdefault"`
-	Title   string `json:"title,omitempty" validate:"required"`
-	Content string `json:"content,omitempty" validate:"required"`
-}
+import (
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/graphql-go/graphql"
+)
 
 var articleType *graphql.Object = graphql.NewObject(graphql.ObjectConfig{
 	Name: "Article",
@@ -18,13 +14,8 @@ var articleType *graphql.Object = graphql.NewObject(graphql.ObjectConfig{
 		"author": &graphql.Field{
 			Type: authorType,
 			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
-				article := params.Source.(Article)
-				for _, author := range authors {
-					if author.Id == article.Author {
-						return author, nil
-					}
-				}
-				return nil, nil
+				article := params.Source.(mock.Article)
+				return loadersFrom(params.Context).authors.Load(article.Author), nil
 			},
 		},
 		"title": &graphql.Field{
@@ -33,9 +24,16 @@ var articleType *graphql.Object = graphql.NewObject(graphql.ObjectConfig{
 		"content": &graphql.Field{
 			Type: graphql.String,
 		},
+		"createdAt": &graphql.Field{
+			Type: graphql.DateTime,
+		},
 	},
 })
 
+var articleConnectionType *graphql.Object = connectionType("Article", articleType)
+
+var articleConnectionArgs graphql.FieldConfigArgument = connectionArgs("Article", mock.ArticleFields)
+
 var articleInputType *graphql.InputObject = graphql.NewInputObject(graphql.InputObjectConfig{
 	Name: "ArticleInput",
 	Fields: graphql.InputObjectConfigFieldMap{
@@ -0,0 +1,89 @@
+package gql
+
+import (
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/graphql-go/graphql"
+)
+
+var authorType *graphql.Object = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Author",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type: graphql.String,
+		},
+		"firstname": &graphql.Field{
+			Type: graphql.String,
+		},
+		"lastname": &graphql.Field{
+			Type: graphql.String,
+		},
+		"username": &graphql.Field{
+			Type: graphql.String,
+		},
+		"role": &graphql.Field{
+			Type: graphql.String,
+		},
+		"createdAt": &graphql.Field{
+			Type: graphql.DateTime,
+		},
+	},
+})
+
+var authorConnectionType *graphql.Object = connectionType("Author", authorType)
+
+// The article fields are added here rather than in the declaration because
+// articleType refers back to authorType.
+func init() {
+	authorType.AddFieldConfig("articles", &graphql.Field{
+		Type: articleConnectionType,
+		Args: articleConnectionArgs,
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			author := params.Source.(mock.Author)
+			query, err := listQuery(params.Args)
+			if err != nil {
+				return nil, err
+			}
+			query.Filters = append(query.Filters, mock.Filter{Field: "author", Values: []string{author.Id}})
+			articles, page, err := mock.ListArticles(query)
+			if err != nil {
+				return nil, mock.AsError(err)
+			}
+			nodes := make([]interface{}, len(articles))
+			for index, article := range articles {
+				nodes[index] = article
+			}
+			return newConnection(nodes, page), nil
+		},
+	})
+	authorType.AddFieldConfig("articleCount", &graphql.Field{
+		Type: graphql.NewNonNull(graphql.Int),
+		Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+			author := params.Source.(mock.Author)
+			return loadersFrom(params.Context).articleCounts.Load(author.Id), nil
+		},
+	})
+}
+
+var authorInputType *graphql.InputObject = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "AuthorInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"id": &graphql.InputObjectFieldConfig{
+			Type: graphql.String,
+		},
+		"firstname": &graphql.InputObjectFieldConfig{
+			Type: graphql.String,
+		},
+		"lastname": &graphql.InputObjectFieldConfig{
+			Type: graphql.String,
+		},
+		"username": &graphql.InputObjectFieldConfig{
+			Type: graphql.String,
+		},
+		"password": &graphql.InputObjectFieldConfig{
+			Type: graphql.String,
+		},
+		"role": &graphql.InputObjectFieldConfig{
+			Type: graphql.String,
+		},
+	},
+})
@@ -0,0 +1,216 @@
+package gql
+
+import (
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/graphql-go/graphql"
+	"strings"
+	"unicode"
+)
+
+type edge struct {
+	Node   interface{} `json:"node"`
+	Cursor string      `json:"cursor"`
+}
+
+type pageInfo struct {
+	HasNextPage     bool   `json:"hasNextPage"`
+	HasPreviousPage bool   `json:"hasPreviousPage"`
+	StartCursor     string `json:"startCursor"`
+	EndCursor       string `json:"endCursor"`
+}
+
+type connection struct {
+	Edges      []edge   `json:"edges"`
+	PageInfo   pageInfo `json:"pageInfo"`
+	TotalCount int      `json:"totalCount"`
+}
+
+func newConnection(nodes []interface{}, page mock.Page) connection {
+	result := connection{
+		Edges: make([]edge, len(nodes)),
+		PageInfo: pageInfo{
+			HasNextPage:     page.HasNext,
+			HasPreviousPage: page.HasPrevious,
+			StartCursor:     page.StartCursor(),
+			EndCursor:       page.EndCursor(),
+		},
+		TotalCount: page.Total,
+	}
+	for index, node := range nodes {
+		result.Edges[index] = edge{Node: node, Cursor: page.Cursors[index]}
+	}
+	return result
+}
+
+var pageInfoType *graphql.Object = graphql.NewObject(graphql.ObjectConfig{
+	Name: "PageInfo",
+	Fields: graphql.Fields{
+		"hasNextPage": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Boolean),
+		},
+		"hasPreviousPage": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Boolean),
+		},
+		"startCursor": &graphql.Field{
+			Type: graphql.String,
+		},
+		"endCursor": &graphql.Field{
+			Type: graphql.String,
+		},
+	},
+})
+
+var orderDirectionType *graphql.Enum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "OrderDirection",
+	Values: graphql.EnumValueConfigMap{
+		"ASC": &graphql.EnumValueConfig{
+			Value: "ASC",
+		},
+		"DESC": &graphql.EnumValueConfig{
+			Value: "DESC",
+		},
+	},
+})
+
+func connectionType(name string, node *graphql.Object) *graphql.Object {
+	edgeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: name + "Edge",
+		Fields: graphql.Fields{
+			"node": &graphql.Field{
+				Type: node,
+			},
+			"cursor": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+	})
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: name + "Connection",
+		Fields: graphql.Fields{
+			"edges": &graphql.Field{
+				Type: graphql.NewList(edgeType),
+			},
+			"pageInfo": &graphql.Field{
+				Type: graphql.NewNonNull(pageInfoType),
+			},
+			"totalCount": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Int),
+			},
+		},
+	})
+}
+
+// connectionArgs builds the Relay pagination arguments plus a <name>Filter
+// input, with an exact and a "Contains" entry per field, and a list of
+// <name>Order inputs for orderBy. Timestamps are only offered for ordering.
+func connectionArgs(name string, fields []string) graphql.FieldConfigArgument {
+	filters := graphql.InputObjectConfigFieldMap{}
+	orderFields := graphql.EnumValueConfigMap{}
+	for _, field := range fields {
+		orderFields[enumName(field)] = &graphql.EnumValueConfig{
+			Value: field,
+		}
+		if field == "createdAt" {
+			continue
+		}
+		filters[field] = &graphql.InputObjectFieldConfig{
+			Type: graphql.String,
+		}
+		filters[field+"Contains"] = &graphql.InputObjectFieldConfig{
+			Type: graphql.String,
+		}
+	}
+	orderType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: name + "Order",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"field": &graphql.InputObjectFieldConfig{
+				Type: graphql.NewNonNull(graphql.NewEnum(graphql.EnumConfig{
+					Name:   name + "OrderField",
+					Values: orderFields,
+				})),
+			},
+			"direction": &graphql.InputObjectFieldConfig{
+				Type:         orderDirectionType,
+				DefaultValue: "ASC",
+			},
+		},
+	})
+	return graphql.FieldConfigArgument{
+		"first": &graphql.ArgumentConfig{
+			Type: graphql.Int,
+		},
+		"after": &graphql.ArgumentConfig{
+			Type: graphql.String,
+		},
+		"last": &graphql.ArgumentConfig{
+			Type: graphql.Int,
+		},
+		"before": &graphql.ArgumentConfig{
+			Type: graphql.String,
+		},
+		"filter": &graphql.ArgumentConfig{
+			Type: graphql.NewInputObject(graphql.InputObjectConfig{
+				Name:   name + "Filter",
+				Fields: filters,
+			}),
+		},
+		"orderBy": &graphql.ArgumentConfig{
+			Type: graphql.NewList(graphql.NewNonNull(orderType)),
+		},
+	}
+}
+
+// listQuery turns the arguments declared by connectionArgs into a query.
+func listQuery(args map[string]interface{}) (mock.ListQuery, error) {
+	var query mock.ListQuery
+	if first, ok := args["first"].(int); ok {
+		if first <= 0 {
+			return mock.ListQuery{}, mock.BadRequest("first must be a positive number")
+		}
+		query.First = first
+	}
+	if last, ok := args["last"].(int); ok {
+		if last <= 0 {
+			return mock.ListQuery{}, mock.BadRequest("last must be a positive number")
+		}
+		query.Last = last
+	}
+	query.After, _ = args["after"].(string)
+	query.Before, _ = args["before"].(string)
+	if filter, ok := args["filter"].(map[string]interface{}); ok {
+		for key, value := range filter {
+			value, ok := value.(string)
+			if !ok {
+				continue
+			}
+			field := strings.TrimSuffix(key, "Contains")
+			query.Filters = append(query.Filters, mock.Filter{
+				Field:    field,
+				Contains: field != key,
+				Values:   []string{value},
+			})
+		}
+	}
+	if orderBy, ok := args["orderBy"].([]interface{}); ok {
+		for _, order := range orderBy {
+			order := order.(map[string]interface{})
+			query.Sort = append(query.Sort, mock.SortField{
+				Field:      order["field"].(string),
+				Descending: order["direction"] == "DESC",
+			})
+		}
+	}
+	return query, nil
+}
+
+// enumName converts a field name such as createdAt into CREATED_AT.
+func enumName(field string) string {
+	var name strings.Builder
+	for index, letter := range field {
+		if unicode.IsUpper(letter) && index > 0 {
+			name.WriteRune('_')
+		}
+		name.WriteRune(unicode.ToUpper(letter))
+	}
+	return name.String()
+}
@@ -0,0 +1,136 @@
+package gql
+
+import (
+	"fmt"
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/graphql-go/graphql"
+	"sort"
+	"strings"
+)
+
+// Guard decides whether a resolver may run. A non-nil error is returned to the
+// client instead of resolving the field.
+type Guard func(params graphql.ResolveParams) error
+
+// public marks fields anyone may resolve. It exists so that leaving a field
+// open is a visible decision rather than an omission.
+func public(params graphql.ResolveParams) error {
+	return nil
+}
+
+func authenticated(params graphql.ResolveParams) error {
+	_, err := authenticate(params.Context)
+	return err
+}
+
+func requirePermission(permission mock.Permission) Guard {
+	return func(params graphql.ResolveParams) error {
+		claims, err := authenticate(params.Context)
+		if err != nil {
+			return err
+		}
+		return asError(mock.Require(claims, permission))
+	}
+}
+
+// ownerOnly admits the owner of the record the field works on, as found by
+// owner, if they hold own, and anybody holding all. Records that do not exist
+// are left for the resolver to report.
+func ownerOnly(owner func(params graphql.ResolveParams) (string, error), own mock.Permission, all mock.Permission) Guard {
+	return func(params graphql.ResolveParams) error {
+		claims, err := authenticate(params.Context)
+		if err != nil {
+			return err
+		}
+		id, err := owner(params)
+		if err == mock.ErrNotFound {
+			return nil
+		} else if err != nil {
+			return mock.AsError(err)
+		}
+		return asError(mock.Authorize(claims, own, all, id))
+	}
+}
+
+// articleOwner looks up the author of the article in the id argument.
+func articleOwner(params graphql.ResolveParams) (string, error) {
+	id, _ := params.Args["id"].(string)
+	article, err := mock.Articles.Get(id)
+	return article.Author, err
+}
+
+// authorArgument returns the author id in the id argument, or in the id of
+// the input object argument named input when one is given.
+func authorArgument(input string) func(params graphql.ResolveParams) (string, error) {
+	return func(params graphql.ResolveParams) (string, error) {
+		if input == "" {
+			id, _ := params.Args["id"].(string)
+			return id, nil
+		}
+		object, _ := params.Args[input].(map[string]interface{})
+		id, _ := object["id"].(string)
+		return id, nil
+	}
+}
+
+func asError(err error) error {
+	if err != nil {
+		return mock.AsError(err)
+	}
+	return nil
+}
+
+// unguarded collects root fields built without guards, and unknownGuards
+// guard entries naming no field; NewSchema refuses to build a schema while
+// there are any.
+var unguarded, unknownGuards []string
+
+// guardFields wraps the resolver of every field with its guards, which run in
+// order before the resolver. Every field needs an entry in guards, even if it
+// is just public.
+func guardFields(object string, fields graphql.Fields, guards map[string][]Guard) graphql.Fields {
+	for name, field := range fields {
+		fieldGuards, ok := guards[name]
+		if !ok || len(fieldGuards) == 0 {
+			unguarded = append(unguarded, object+"."+name)
+			continue
+		}
+		resolve := field.Resolve
+		field.Resolve = func(params graphql.ResolveParams) (interface{}, error) {
+			for _, guard := range fieldGuards {
+				if err := guard(params); err != nil {
+					return nil, err
+				}
+			}
+			return resolve(params)
+		}
+	}
+	for name := range guards {
+		if _, ok := fields[name]; !ok {
+			unknownGuards = append(unknownGuards, object+"."+name)
+		}
+	}
+	return fields
+}
+
+func checkGuards() error {
+	var problems []string
+	for _, check := range []struct {
+		message string
+		names   []string
+	}{
+		{"fields without guards", unguarded},
+		{"guards for unknown fields", unknownGuards},
+	} {
+		if len(check.names) == 0 {
+			continue
+		}
+		names := append([]string{}, check.names...)
+		sort.Strings(names)
+		problems = append(problems, check.message+": "+strings.Join(names, ", "))
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s", strings.Join(problems, "; "))
+}
@@ -0,0 +1,84 @@
+package gql
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/graphql-go/graphql"
+)
+
+// keepGuardChecks restores the fields recorded by guardFields once the test
+// ends, so a test can register broken fields without breaking NewSchema for
+// the others.
+func keepGuardChecks(t *testing.T) {
+	fields, guards := unguarded, unknownGuards
+	t.Cleanup(func() { unguarded, unknownGuards = fields, guards })
+}
+
+func TestNewSchemaChecksGuards(t *testing.T) {
+	if _, err := NewSchema(); err != nil {
+		t.Fatalf("schema without problems: %v", err)
+	}
+	resolve := func(params graphql.ResolveParams) (interface{}, error) { return nil, nil }
+	tests := []struct {
+		name     string
+		fields   graphql.Fields
+		guards   map[string][]Guard
+		expected string
+	}{
+		{"field without guards", graphql.Fields{
+			"guarded":   &graphql.Field{Type: graphql.String, Resolve: resolve},
+			"unguarded": &graphql.Field{Type: graphql.String, Resolve: resolve},
+		}, map[string][]Guard{"guarded": {public}}, "fields without guards: Test.unguarded"},
+		{"field with an empty guard list", graphql.Fields{
+			"field": &graphql.Field{Type: graphql.String, Resolve: resolve},
+		}, map[string][]Guard{"field": {}}, "fields without guards: Test.field"},
+		{"guard for an unknown field", graphql.Fields{
+			"field": &graphql.Field{Type: graphql.String, Resolve: resolve},
+		}, map[string][]Guard{"field": {public}, "renamed": {public}}, "guards for unknown fields: Test.renamed"},
+	}
+	keepGuardChecks(t)
+	for _, test := range tests {
+		unguarded, unknownGuards = nil, nil
+		guardFields("Test", test.fields, test.guards)
+		_, err := NewSchema()
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("%s: expected %q, got %v", test.name, test.expected, err)
+		}
+	}
+}
+
+func TestGuardStopsResolver(t *testing.T) {
+	keepGuardChecks(t)
+	var calls []string
+	guard := func(name string, err error) Guard {
+		return func(params graphql.ResolveParams) error {
+			calls = append(calls, name)
+			return err
+		}
+	}
+	resolve := func(params graphql.ResolveParams) (interface{}, error) {
+		calls = append(calls, "resolve")
+		return "resolved", nil
+	}
+	forbidden := mock.AsError(mock.ErrForbidden)
+	fields := guardFields("Test", graphql.Fields{
+		"open":   &graphql.Field{Type: graphql.String, Resolve: resolve},
+		"closed": &graphql.Field{Type: graphql.String, Resolve: resolve},
+	}, map[string][]Guard{
+		"open":   {guard("first", nil), guard("second", nil)},
+		"closed": {guard("first", forbidden), guard("second", nil)},
+	})
+
+	value, err := fields["open"].Resolve(graphql.ResolveParams{})
+	if err != nil || value != "resolved" || strings.Join(calls, ",") != "first,second,resolve" {
+		t.Errorf("passing guards: got %v, %v after %v", value, err, calls)
+	}
+	calls = nil
+	value, err = fields["closed"].Resolve(graphql.ResolveParams{})
+	if !errors.Is(err, forbidden) || value != nil || strings.Join(calls, ",") != "first" {
+		t.Errorf("failing guard: got %v, %v after %v", value, err, calls)
+	}
+}
@@ -0,0 +1,111 @@
+package gql
+
+import (
+	"context"
+	"github.com/Bone1289/go-web-example/mock"
+	"sync"
+)
+
+type loadResult struct {
+	value interface{}
+	err   error
+}
+
+// loader batches and caches lookups for a single request. Load only queues
+// the key and returns a thunk; graphql-go runs thunks once every sibling
+// field has been resolved, so the first thunk to run fetches all queued keys
+// with one call to fetch.
+type loader struct {
+	fetch   func(keys []string) (map[string]interface{}, error)
+	mutex   sync.Mutex
+	pending []string
+	results map[string]*loadResult
+}
+
+func newLoader(fetch func(keys []string) (map[string]interface{}, error)) *loader {
+	return &loader{fetch: fetch, results: map[string]*loadResult{}}
+}
+
+func (loader *loader) Load(key string) func() (interface{}, error) {
+	loader.mutex.Lock()
+	if _, ok := loader.results[key]; !ok {
+		loader.results[key] = nil
+		loader.pending = append(loader.pending, key)
+	}
+	loader.mutex.Unlock()
+	return func() (interface{}, error) {
+		loader.mutex.Lock()
+		defer loader.mutex.Unlock()
+		if loader.results[key] == nil {
+			loader.dispatch()
+		}
+		result := loader.results[key]
+		return result.value, result.err
+	}
+}
+
+// dispatch fetches every pending key. The caller holds the mutex.
+func (loader *loader) dispatch() {
+	keys := loader.pending
+	loader.pending = nil
+	values, err := loader.fetch(keys)
+	for _, key := range keys {
+		loader.results[key] = &loadResult{value: values[key], err: err}
+	}
+}
+
+// loaders holds the loaders shared by the resolvers of one request.
+type loaders struct {
+	authors       *loader
+	articleCounts *loader
+}
+
+func newLoaders() *loaders {
+	return &loaders{
+		authors: newLoader(func(ids []string) (map[string]interface{}, error) {
+			authors, err := mock.Authors.GetMany(ids)
+			if err != nil {
+				return nil, mock.AsError(err)
+			}
+			values := make(map[string]interface{}, len(authors))
+			for _, author := range authors {
+				values[author.Id] = author
+			}
+			return values, nil
+		}),
+		articleCounts: newLoader(func(ids []string) (map[string]interface{}, error) {
+			articles, err := mock.Articles.All()
+			if err != nil {
+				return nil, mock.AsError(err)
+			}
+			values := make(map[string]interface{}, len(ids))
+			for _, id := range ids {
+				values[id] = 0
+			}
+			for _, article := range articles {
+				if count, ok := values[article.Author]; ok {
+					values[article.Author] = count.(int) + 1
+				}
+			}
+			return values, nil
+		}),
+	}
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loadersKey{}, newLoaders())
+}
+
+// loadersFrom returns the request's loaders. Contexts that were not prepared
+// by Handler get fresh ones, which still work but only batch within the call.
+func loadersFrom(ctx context.Context) *loaders {
+	if ctx == nil {
+		return newLoaders()
+	}
+	if loaders, ok := ctx.Value(loadersKey{}).(*loaders); ok {
+		return loaders
+	}
+	return newLoaders()
+}
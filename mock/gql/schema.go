@@ -0,0 +1,358 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/gorilla/mux"
+	"github.com/graphql-go/graphql"
+	"github.com/mitchellh/mapstructure"
+	"net/http"
+)
+
+var rootQuery *graphql.Object = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Query",
+	Fields: guardFields("Query", graphql.Fields{
+		"authors": &graphql.Field{
+			Type: authorConnectionType,
+			Args: connectionArgs("Author", mock.AuthorFields),
+			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+				query, err := listQuery(params.Args)
+				if err != nil {
+					return nil, err
+				}
+				authors, page, err := mock.ListAuthors(query)
+				if err != nil {
+					return nil, mock.AsError(err)
+				}
+				nodes := make([]interface{}, len(authors))
+				for index, author := range authors {
+					nodes[index] = author
+				}
+				return newConnection(nodes, page), nil
+			},
+		},
+		"author": &graphql.Field{
+			Type: authorType,
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+			Resolve: func(param graphql.ResolveParams) (interface{}, error) {
+				id := param.Args["id"].(string)
+				author, err := mock.Authors.Get(id)
+				if err == mock.ErrNotFound {
+					return nil, nil
+				}
+				return author, err
+			},
+		},
+		"articles": &graphql.Field{
+			Type: articleConnectionType,
+			Args: articleConnectionArgs,
+			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+				query, err := listQuery(params.Args)
+				if err != nil {
+					return nil, err
+				}
+				articles, page, err := mock.ListArticles(query)
+				if err != nil {
+					return nil, mock.AsError(err)
+				}
+				nodes := make([]interface{}, len(articles))
+				for index, article := range articles {
+					nodes[index] = article
+				}
+				return newConnection(nodes, page), nil
+			},
+		},
+		"article": &graphql.Field{
+			Type: articleType,
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+			Resolve: func(param graphql.ResolveParams) (interface{}, error) {
+				id := param.Args["id"].(string)
+				article, err := mock.Articles.Get(id)
+				if err == mock.ErrNotFound {
+					return nil, nil
+				}
+				return article, err
+			},
+		},
+	}, map[string][]Guard{
+		"authors":  {public},
+		"author":   {public},
+		"articles": {public},
+		"article":  {public},
+	}),
+})
+
+var rootMutation *graphql.Object = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Mutation",
+	Fields: guardFields("Mutation", graphql.Fields{
+		"createArticle": &graphql.Field{
+			Type: graphql.NewList(articleType),
+			Args: graphql.FieldConfigArgument{
+				"article": &graphql.ArgumentConfig{
+					Type: articleInputType,
+				},
+			},
+			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+				var article mock.Article
+				mapstructure.Decode(params.Args["article"], &article)
+				claims, err := mock.ClaimsFrom(params.Context)
+				if err != nil {
+					return nil, mock.AsError(err)
+				}
+				_, err = mock.CreateArticle(claims, article)
+				if err != nil {
+					return nil, mock.AsError(err)
+				}
+				return mock.Articles.All()
+			},
+		},
+		"updateArticle": &graphql.Field{
+			Type: graphql.NewList(articleType),
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"article": &graphql.ArgumentConfig{
+					Type: articleInputType,
+				},
+			},
+			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+				var changes mock.Article
+				mapstructure.Decode(params.Args["article"], &changes)
+				claims, err := mock.ClaimsFrom(params.Context)
+				if err != nil {
+					return nil, mock.AsError(err)
+				}
+				_, err = mock.UpdateArticle(params.Args["id"].(string), claims, changes)
+				if err == mock.ErrNotFound {
+					return nil, nil
+				} else if err != nil {
+					return nil, mock.AsError(err)
+				}
+				return mock.Articles.All()
+			},
+		},
+		"deleteArticle": &graphql.Field{
+			Type: graphql.NewList(articleType),
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+				claims, err := mock.ClaimsFrom(params.Context)
+				if err != nil {
+					return nil, mock.AsError(err)
+				}
+				err = mock.DeleteArticle(params.Args["id"].(string), claims)
+				if err == mock.ErrNotFound {
+					return nil, nil
+				} else if err != nil {
+					return nil, mock.AsError(err)
+				}
+				return mock.Articles.All()
+			},
+		},
+		"register": &graphql.Field{
+			Type: graphql.NewList(authorType),
+			Args: graphql.FieldConfigArgument{
+				"author": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(authorInputType),
+				},
+			},
+			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+				var author mock.Author
+				mapstructure.Decode(params.Args["author"], &author)
+				_, err := mock.RegisterAuthor(author)
+				if err != nil {
+					return nil, mock.AsError(err)
+				}
+				return mock.Authors.All()
+			},
+		},
+		"login": &graphql.Field{
+			Type: tokensType,
+			Args: graphql.FieldConfigArgument{
+				"username": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"password": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+				author, err := mock.VerifyCredentials(mock.Author{
+					Username: params.Args["username"].(string),
+					Password: params.Args["password"].(string),
+				})
+				if err != nil {
+					return nil, mock.AsError(err)
+				}
+				tokens, err := mock.NewTokens(author)
+				if err != nil {
+					return nil, mock.AsError(err)
+				}
+				return tokens, nil
+			},
+		},
+		"refreshToken": &graphql.Field{
+			Type: tokensType,
+			Args: graphql.FieldConfigArgument{
+				"refreshToken": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+				tokens, err := mock.Refresh(params.Args["refreshToken"].(string))
+				if err != nil {
+					return nil, mock.AsError(err)
+				}
+				return tokens, nil
+			},
+		},
+		"logout": &graphql.Field{
+			Type: graphql.Boolean,
+			Args: graphql.FieldConfigArgument{
+				"refreshToken": &graphql.ArgumentConfig{
+					Type: graphql.String,
+				},
+			},
+			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+				claims, err := mock.ClaimsFrom(params.Context)
+				if err != nil {
+					return nil, mock.AsError(err)
+				}
+				refreshToken, _ := params.Args["refreshToken"].(string)
+				err = mock.Logout(claims, refreshToken)
+				if err != nil {
+					return nil, mock.AsError(err)
+				}
+				return true, nil
+			},
+		},
+		"updateAuthor": &graphql.Field{
+			Type: graphql.NewList(authorType),
+			Args: graphql.FieldConfigArgument{
+				"author": &graphql.ArgumentConfig{
+					Type: authorInputType,
+				},
+			},
+			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+				var changes mock.Author
+				mapstructure.Decode(params.Args["author"], &changes)
+				claims, err := mock.ClaimsFrom(params.Context)
+				if err != nil {
+					return nil, mock.AsError(err)
+				}
+				_, err = mock.UpdateAuthor(changes.Id, claims, changes)
+				if err == mock.ErrNotFound {
+					return nil, nil
+				} else if err != nil {
+					return nil, mock.AsError(err)
+				}
+				return mock.Authors.All()
+			},
+		},
+		"deleteAuthor": &graphql.Field{
+			Type: graphql.NewList(authorType),
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			},
+			Resolve: func(params graphql.ResolveParams) (interface{}, error) {
+				claims, err := mock.ClaimsFrom(params.Context)
+				if err != nil {
+					return nil, mock.AsError(err)
+				}
+				err = mock.DeleteAuthor(params.Args["id"].(string), claims)
+				if err == mock.ErrNotFound {
+					return nil, nil
+				} else if err != nil {
+					return nil, mock.AsError(err)
+				}
+				return mock.Authors.All()
+			},
+		},
+	}, map[string][]Guard{
+		"createArticle": {requirePermission(mock.PermCreateArticle)},
+		"updateArticle": {ownerOnly(articleOwner, mock.PermUpdateOwnArticle, mock.PermUpdateAnyArticle)},
+		"deleteArticle": {ownerOnly(articleOwner, mock.PermDeleteOwnArticle, mock.PermDeleteAnyArticle)},
+		"register":      {public},
+		"login":         {public},
+		"refreshToken":  {public},
+		"logout":        {authenticated},
+		"updateAuthor":  {ownerOnly(authorArgument("author"), mock.PermUpdateOwnAuthor, mock.PermUpdateAnyAuthor)},
+		"deleteAuthor":  {ownerOnly(authorArgument(""), mock.PermDeleteOwnAuthor, mock.PermDeleteAnyAuthor)},
+	}),
+})
+
+type GraphQLPayload struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// authenticate returns the claims of the token the request was made with.
+func authenticate(ctx context.Context) (mock.CustomJWTClaims, error) {
+	claims, err := mock.ClaimsFrom(ctx)
+	if err != nil {
+		return mock.CustomJWTClaims{}, mock.AsError(err)
+	}
+	return claims, nil
+}
+
+// NewSchema builds the schema, failing if a root field was added without
+// declaring its guards.
+func NewSchema() (graphql.Schema, error) {
+	if err := checkGuards(); err != nil {
+		return graphql.Schema{}, err
+	}
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    rootQuery,
+		Mutation: rootMutation,
+	})
+}
+
+func Handler(schema graphql.Schema) http.HandlerFunc {
+	return func(response http.ResponseWriter, request *http.Request) {
+		var payload GraphQLPayload
+		json.NewDecoder(request.Body).Decode(&payload)
+		token, err := mock.BearerToken(request)
+		if err == nil && token == "" && mock.QueryToken {
+			token = request.URL.Query().Get("token")
+		}
+		var claims mock.CustomJWTClaims
+		if err == nil {
+			claims, err = mock.Authenticate(token)
+		}
+		ctx := mock.WithClaims(request.Context(), claims, err)
+		result := graphql.Do(graphql.Params{
+			Schema:         schema,
+			RequestString:  payload.Query,
+			VariableValues: payload.Variables,
+			Context:        withLoaders(ctx),
+		})
+		json.NewEncoder(response).Encode(result)
+	}
+}
+
+// Register mounts the GraphQL endpoint on router. The /login, /token/refresh,
+// /logout, /author and JWKS endpoints of the REST API are mounted separately
+// by the caller.
+func Register(router *mux.Router) error {
+	schema, err := NewSchema()
+	if err != nil {
+		return err
+	}
+	router.HandleFunc("/graphql", Handler(schema))
+	return nil
+}
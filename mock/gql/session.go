@@ -0,0 +1,17 @@
+package gql
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+var tokensType *graphql.Object = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Tokens",
+	Fields: graphql.Fields{
+		"token": &graphql.Field{
+			Type: graphql.String,
+		},
+		"refreshToken": &graphql.Field{
+			Type: graphql.String,
+		},
+	},
+})
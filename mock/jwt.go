@@ -0,0 +1,83 @@
+package mock
+
+import (
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+type CustomJWTClaims struct {
+	Id   string `json:"id"`
+	Role string `json:"role,omitempty"`
+	jwt.RegisteredClaims
+}
+
+var JwtSecret []byte = []byte("thepolyglotdeveloper")
+var JwtIssuer = "The Polyglot Developer"
+var JwtAudience = "go-web-example"
+var TokenLifetime = time.Hour
+
+// ClockSkew is how far the clocks of token issuers may be off: exp, nbf and
+// iat are checked with this much leeway.
+var ClockSkew = 30 * time.Second
+
+func NewToken(author Author) (string, error) {
+	now := time.Now()
+	claims := CustomJWTClaims{
+		Id:   author.Id,
+		Role: author.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.Must(uuid.NewV4()).String(),
+			Issuer:    JwtIssuer,
+			Audience:  jwt.ClaimStrings{JwtAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(TokenLifetime)),
+		},
+	}
+	if SigningKey != nil {
+		token := jwt.NewWithClaims(SigningKey.Method, claims)
+		token.Header["kid"] = SigningKey.Id
+		return token.SignedString(SigningKey.Private)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(JwtSecret)
+}
+
+// ValidateJWT verifies the signature of t and checks that it was issued by
+// JwtIssuer for JwtAudience, is within its nbf to exp window, was not issued
+// in the future and has not been logged out.
+func ValidateJWT(t string) (CustomJWTClaims, error) {
+	var claims CustomJWTClaims
+	_, err := jwt.ParseWithClaims(t, &claims, verificationKey,
+		jwt.WithIssuer(JwtIssuer),
+		jwt.WithAudience(JwtAudience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(ClockSkew),
+	)
+	if err != nil {
+		return CustomJWTClaims{}, err
+	}
+	if claims.IssuedAt == nil || claims.NotBefore == nil {
+		return CustomJWTClaims{}, errors.New("token is missing the iat or nbf claim")
+	}
+	if IsRevoked(claims) {
+		return CustomJWTClaims{}, errors.New("token has been revoked")
+	}
+	return claims, nil
+}
+
+// Authenticate validates a bearer token, reporting a missing token as
+// ErrUnauthorized and any other failure as ErrInvalidToken.
+func Authenticate(token string) (CustomJWTClaims, error) {
+	if token == "" {
+		return CustomJWTClaims{}, ErrUnauthorized
+	}
+	claims, err := ValidateJWT(token)
+	if err != nil {
+		return CustomJWTClaims{}, ErrInvalidToken
+	}
+	return claims, nil
+}
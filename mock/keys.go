@@ -0,0 +1,178 @@
+package mock
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+)
+
+// Key is an asymmetric key tokens are signed or verified with. Private is nil
+// for keys that are only accepted for verification.
+type Key struct {
+	Id      string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+// SigningKey signs new tokens when set; otherwise tokens are signed with the
+// shared JwtSecret. VerificationKeys lists every key tokens are accepted from,
+// the signing key included, so retired keys keep working until their tokens
+// expire.
+var SigningKey *Key
+var VerificationKeys []*Key
+
+// LoadKey reads a PEM encoded RSA, ECDSA or Ed25519 key. Private keys can
+// sign, public keys only verify. The key id is the RFC 7638 thumbprint.
+func LoadKey(path string) (*Key, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	var parsed interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	key := &Key{Public: parsed}
+	if signer, ok := parsed.(crypto.Signer); ok {
+		key.Private = signer
+		key.Public = signer.Public()
+	}
+	switch public := key.Public.(type) {
+	case *rsa.PublicKey:
+		key.Method = jwt.SigningMethodRS256
+	case *ecdsa.PublicKey:
+		switch public.Curve {
+		case elliptic.P256():
+			key.Method = jwt.SigningMethodES256
+		case elliptic.P384():
+			key.Method = jwt.SigningMethodES384
+		case elliptic.P521():
+			key.Method = jwt.SigningMethodES512
+		default:
+			return nil, errors.New("unsupported elliptic curve")
+		}
+	case ed25519.PublicKey:
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", public)
+	}
+	key.Id = thumbprint(key.JWK())
+	return key, nil
+}
+
+// JWK is the public part of a key in JSON Web Key form.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+func (key *Key) JWK() JWK {
+	jwk := JWK{Kid: key.Id, Use: "sig", Alg: key.Method.Alg()}
+	encode := base64.RawURLEncoding.EncodeToString
+	switch public := key.Public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = encode(public.N.Bytes())
+		jwk.E = encode(big.NewInt(int64(public.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (public.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = public.Curve.Params().Name
+		jwk.X = encode(padded(public.X, size))
+		jwk.Y = encode(padded(public.Y, size))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = encode(public)
+	}
+	return jwk
+}
+
+func padded(value *big.Int, size int) []byte {
+	data := value.Bytes()
+	return append(make([]byte, size-len(data)), data...)
+}
+
+// thumbprint hashes the required members of jwk in lexicographic order, which
+// is the order encoding/json writes map keys in.
+func thumbprint(jwk JWK) string {
+	members := map[string]string{"kty": jwk.Kty}
+	switch jwk.Kty {
+	case "RSA":
+		members["n"], members["e"] = jwk.N, jwk.E
+	case "EC":
+		members["crv"], members["x"], members["y"] = jwk.Crv, jwk.X, jwk.Y
+	case "OKP":
+		members["crv"], members["x"] = jwk.Crv, jwk.X
+	}
+	data, _ := json.Marshal(members)
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// verificationKey picks the key a token claims to be signed with, making sure
+// the algorithm in its header is the one that key is used with.
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	if SigningKey == nil {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("Unexpected signing method %v", token.Header["alg"])
+		}
+		return JwtSecret, nil
+	}
+	id, _ := token.Header["kid"].(string)
+	for _, key := range VerificationKeys {
+		if key.Id == id {
+			if key.Method.Alg() != token.Method.Alg() {
+				return nil, fmt.Errorf("Unexpected signing method %v", token.Header["alg"])
+			}
+			return key.Public, nil
+		}
+	}
+	return nil, fmt.Errorf("Unknown key %q", id)
+}
+
+func JWKSEndpoint(response http.ResponseWriter, request *http.Request) {
+	response.Header().Add("content-type", "application/json")
+	keys := make([]JWK, len(VerificationKeys))
+	for index, key := range VerificationKeys {
+		keys[index] = key.JWK()
+	}
+	json.NewEncoder(response).Encode(map[string][]JWK{"keys": keys})
+}
@@ -0,0 +1,120 @@
+package mock_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/Bone1289/go-web-example/mock/mocktest"
+)
+
+func marshalPKCS8(t *testing.T, key interface{}) []byte {
+	t.Helper()
+	data, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func marshalPublic(t *testing.T, key crypto.PublicKey) []byte {
+	t.Helper()
+	data, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func loadKey(t *testing.T, path string) *mock.Key {
+	t.Helper()
+	key, err := mock.LoadKey(path)
+	if err != nil {
+		t.Fatalf("%s: %v", filepath.Base(path), err)
+	}
+	return key
+}
+
+func TestLoadKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ecData, _ := x509.MarshalECPrivateKey(ecKey)
+	edPublic, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	tests := []struct {
+		name    string
+		path    string
+		alg     string
+		private bool
+		public  crypto.PublicKey
+	}{
+		{"RSA PKCS1", mocktest.WritePEM(t, dir, "rsa1.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)), "RS256", true, &rsaKey.PublicKey},
+		{"RSA PKCS8", mocktest.WritePEM(t, dir, "rsa8.pem", "PRIVATE KEY", marshalPKCS8(t, rsaKey)), "RS256", true, &rsaKey.PublicKey},
+		{"RSA public", mocktest.WritePEM(t, dir, "rsa.pub", "PUBLIC KEY", marshalPublic(t, &rsaKey.PublicKey)), "RS256", false, &rsaKey.PublicKey},
+		{"P-256 SEC1", mocktest.WritePEM(t, dir, "ec1.pem", "EC PRIVATE KEY", ecData), "ES256", true, &ecKey.PublicKey},
+		{"P-256 PKCS8", mocktest.WritePEM(t, dir, "ec8.pem", "PRIVATE KEY", marshalPKCS8(t, ecKey)), "ES256", true, &ecKey.PublicKey},
+		{"P-256 public", mocktest.WritePEM(t, dir, "ec.pub", "PUBLIC KEY", marshalPublic(t, &ecKey.PublicKey)), "ES256", false, &ecKey.PublicKey},
+		{"Ed25519 PKCS8", mocktest.WritePEM(t, dir, "ed.pem", "PRIVATE KEY", marshalPKCS8(t, edKey)), "EdDSA", true, edPublic},
+		{"Ed25519 public", mocktest.WritePEM(t, dir, "ed.pub", "PUBLIC KEY", marshalPublic(t, edPublic)), "EdDSA", false, edPublic},
+	}
+	ids := map[string]string{}
+	for _, test := range tests {
+		key := loadKey(t, test.path)
+		if key.Method.Alg() != test.alg {
+			t.Errorf("%s: expected %s, got %s", test.name, test.alg, key.Method.Alg())
+		}
+		if (key.Private != nil) != test.private {
+			t.Errorf("%s: expected a private key: %v", test.name, test.private)
+		}
+		if publicKey, ok := key.Public.(interface{ Equal(crypto.PublicKey) bool }); !ok || !publicKey.Equal(test.public) {
+			t.Errorf("%s: unexpected public key %T", test.name, key.Public)
+		}
+		// Every encoding of the same key pair has the same key id.
+		if id, ok := ids[test.alg]; ok && id != key.Id {
+			t.Errorf("%s: expected key id %s, got %s", test.name, id, key.Id)
+		}
+		ids[test.alg] = key.Id
+	}
+
+	for _, path := range []string{
+		filepath.Join(dir, "missing.pem"),
+		mocktest.WritePEM(t, dir, "cert.pem", "CERTIFICATE", []byte("not a certificate")),
+		mocktest.WritePEM(t, dir, "broken.pem", "PRIVATE KEY", []byte("not a key")),
+	} {
+		if _, err := mock.LoadKey(path); err == nil {
+			t.Errorf("%s: expected an error", filepath.Base(path))
+		}
+	}
+}
+
+// TestKeyThumbprint checks the key id against the example of RFC 7638,
+// section 3.1.
+func TestKeyThumbprint(t *testing.T) {
+	n, _ := base64.RawURLEncoding.DecodeString("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	public := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}
+	dir, err := ioutil.TempDir("", "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	key := loadKey(t, mocktest.WritePEM(t, dir, "rfc7638.pub", "PUBLIC KEY", marshalPublic(t, public)))
+	if key.Id != "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs" {
+		t.Errorf("unexpected thumbprint %s", key.Id)
+	}
+}
@@ -0,0 +1,45 @@
+package mocktest
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+)
+
+// WritePEM stores a single PEM block in dir and returns its path.
+func WritePEM(t *testing.T, dir string, name string, blockType string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// Key loads key, a private key or a public key only, through mock.LoadKey.
+func Key(t *testing.T, key interface{}) *mock.Key {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "mocktest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var path string
+	if data, err := x509.MarshalPKCS8PrivateKey(key); err == nil {
+		path = WritePEM(t, dir, "key.pem", "PRIVATE KEY", data)
+	} else if data, err := x509.MarshalPKIXPublicKey(key); err == nil {
+		path = WritePEM(t, dir, "key.pub", "PUBLIC KEY", data)
+	} else {
+		t.Fatal(err)
+	}
+	loaded, err := mock.LoadKey(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loaded
+}
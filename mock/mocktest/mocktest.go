@@ -0,0 +1,160 @@
+// Package mocktest holds the fixtures shared by the tests of the mock
+// servers: a server over fresh stores, and helpers to call it over REST and
+// GraphQL as an author of a given role.
+package mocktest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/Bone1289/go-web-example/mock/gql"
+	uuid "github.com/satori/go.uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Password is the password of every author CreateAndLogin stores.
+const Password = "secret"
+
+// NewServer opens new seeded stores of kind, "memory" or "file", in a temporary
+// directory and serves handler over them until the test ends.
+func NewServer(t *testing.T, kind string, handler http.Handler) *httptest.Server {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "mocktest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mock.Authors, mock.Articles, err = mock.OpenStores(kind, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(func() {
+		server.Close()
+		os.RemoveAll(dir)
+	})
+	return server
+}
+
+// Call sends body as JSON, authenticated with token unless it is empty,
+// decodes the response into out when given and returns the status code.
+func Call(t *testing.T, method string, url string, token string, body interface{}, out interface{}) int {
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	request, err := http.NewRequest(method, url, &payload)
+	if err != nil {
+		t.Error(err)
+		return 0
+	}
+	if token != "" {
+		request.Header.Set("authorization", "Bearer "+token)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Error(err)
+		return 0
+	}
+	defer response.Body.Close()
+	if out != nil {
+		json.NewDecoder(response.Body).Decode(out)
+	}
+	return response.StatusCode
+}
+
+// CreateAndLogin stores an author with a cheap bcrypt hash, so tests spend
+// their time on requests rather than on password hashing, and logs in as them
+// through /login.
+func CreateAndLogin(t *testing.T, server *httptest.Server, username string) (mock.Author, string) {
+	t.Helper()
+	hash, _ := bcrypt.GenerateFromPassword([]byte(Password), bcrypt.MinCost)
+	author := mock.Author{
+		Id:        uuid.Must(uuid.NewV4()).String(),
+		Firstname: "Stress",
+		Lastname:  "Test",
+		Username:  username,
+		Password:  string(hash),
+	}
+	if err := mock.Authors.Create(author); err != nil {
+		t.Fatal(err)
+	}
+	var login map[string]string
+	Call(t, "POST", server.URL+"/login", "", mock.Author{Username: username, Password: Password}, &login)
+	if login["token"] == "" {
+		t.Fatalf("login for %s failed: %v", username, login)
+	}
+	return author, login["token"]
+}
+
+// CreateWithRole stores an author holding role and returns a token carrying
+// it.
+func CreateWithRole(t *testing.T, server *httptest.Server, username string, role string) (mock.Author, string) {
+	t.Helper()
+	author, _ := CreateAndLogin(t, server, username)
+	author, err := mock.Authors.Update(author.Id, func(author *mock.Author) error {
+		author.Role = role
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := mock.NewToken(author)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return author, token
+}
+
+type GraphQLError struct {
+	Message    string `json:"message"`
+	Extensions struct {
+		Code    string            `json:"code"`
+		Details []mock.FieldError `json:"details"`
+	} `json:"extensions"`
+}
+
+type GraphQLResult struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []GraphQLError             `json:"errors"`
+}
+
+// Query runs payload against /graphql and fails the test on any GraphQL
+// error.
+func Query(t *testing.T, server *httptest.Server, token string, payload gql.GraphQLPayload) GraphQLResult {
+	result := QueryResult(t, server, token, payload)
+	for _, err := range result.Errors {
+		t.Errorf("graphql error: %s", err.Message)
+	}
+	return result
+}
+
+// QueryResult runs payload and returns its errors along with the data, for
+// tests of the paths that are meant to fail.
+func QueryResult(t *testing.T, server *httptest.Server, token string, payload gql.GraphQLPayload) GraphQLResult {
+	var result GraphQLResult
+	var body bytes.Buffer
+	json.NewEncoder(&body).Encode(payload)
+	request, err := http.NewRequest("POST", server.URL+"/graphql", &body)
+	if err != nil {
+		t.Error(err)
+		return result
+	}
+	request.Header.Set("content-type", "application/json")
+	if token != "" {
+		request.Header.Set("authorization", "Bearer "+token)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Error(err)
+		return result
+	}
+	defer response.Body.Close()
+	json.NewDecoder(response.Body).Decode(&result)
+	return result
+}
@@ -0,0 +1,71 @@
+package mock
+
+// Roles an author can have. Newly registered authors get RoleAuthor, and only
+// an admin can hand out any other role.
+const (
+	RoleAdmin  = "admin"
+	RoleEditor = "editor"
+	RoleAuthor = "author"
+	RoleReader = "reader"
+)
+
+type Permission string
+
+// Permissions come in "own" and "any" pairs where a record has an owner: the
+// "own" permission only applies to records the caller owns.
+const (
+	PermCreateArticle    Permission = "article:create"
+	PermUpdateOwnArticle Permission = "article:update:own"
+	PermUpdateAnyArticle Permission = "article:update:any"
+	PermDeleteOwnArticle Permission = "article:delete:own"
+	PermDeleteAnyArticle Permission = "article:delete:any"
+	PermUpdateOwnAuthor  Permission = "author:update:own"
+	PermUpdateAnyAuthor  Permission = "author:update:any"
+	PermDeleteOwnAuthor  Permission = "author:delete:own"
+	PermDeleteAnyAuthor  Permission = "author:delete:any"
+	PermAssignRoles      Permission = "author:roles"
+)
+
+// Policy grants each role its permissions. Reading is public and needs none.
+var Policy = map[string][]Permission{
+	RoleReader: {PermUpdateOwnAuthor, PermDeleteOwnAuthor},
+	RoleAuthor: {PermUpdateOwnAuthor, PermDeleteOwnAuthor, PermCreateArticle, PermUpdateOwnArticle, PermDeleteOwnArticle},
+	RoleEditor: {PermUpdateOwnAuthor, PermDeleteOwnAuthor, PermCreateArticle, PermUpdateAnyArticle, PermDeleteAnyArticle},
+	RoleAdmin: {
+		PermCreateArticle, PermUpdateAnyArticle, PermDeleteAnyArticle,
+		PermUpdateAnyAuthor, PermDeleteAnyAuthor, PermAssignRoles,
+	},
+}
+
+// Can reports whether the role in claims grants permission. Tokens issued
+// before roles existed carry none and count as RoleAuthor; claims without an
+// author id, such as the zero value, grant nothing.
+func (claims CustomJWTClaims) Can(permission Permission) bool {
+	role := claims.Role
+	if role == "" && claims.Id != "" {
+		role = RoleAuthor
+	}
+	for _, granted := range Policy[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize checks an action on a record owned by owner, which needs the
+// "any" permission, all, unless the caller is the owner and holds own.
+func Authorize(claims CustomJWTClaims, own Permission, all Permission, owner string) error {
+	if claims.Can(all) || (claims.Id == owner && claims.Can(own)) {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// Require fails with ErrForbidden unless claims grant permission.
+func Require(claims CustomJWTClaims, permission Permission) error {
+	if !claims.Can(permission) {
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package mock_test
+
+import (
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+)
+
+func TestCan(t *testing.T) {
+	if (mock.CustomJWTClaims{}).Can(mock.PermCreateArticle) {
+		t.Error("empty claims grant article:create")
+	}
+	if !(mock.CustomJWTClaims{Id: "legacy"}).Can(mock.PermCreateArticle) {
+		t.Error("claims without a role do not count as an author")
+	}
+	if err := mock.Authorize(mock.CustomJWTClaims{}, mock.PermUpdateOwnAuthor, mock.PermUpdateAnyAuthor, ""); err != mock.ErrForbidden {
+		t.Errorf("empty claims own a record without owner: %v", err)
+	}
+}
@@ -0,0 +1,243 @@
+package mock
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type SortField struct {
+	Field      string
+	Descending bool
+}
+
+// Filter matches a field exactly, or case-insensitively as a substring when
+// Contains is set. Several values are alternatives.
+type Filter struct {
+	Field    string
+	Contains bool
+	Values   []string
+}
+
+// ListQuery selects a window of a list: items are filtered, sorted by the
+// requested fields with the id as final tie-breaker, then cut down by the
+// cursors, the offset and finally the First/Last counts.
+type ListQuery struct {
+	Filters []Filter
+	Sort    []SortField
+	After   string
+	Before  string
+	Offset  int
+	First   int
+	Last    int
+}
+
+// Page describes the window a ListQuery selected. Cursors holds one opaque
+// cursor per selected item.
+type Page struct {
+	Total       int
+	Offset      int
+	HasPrevious bool
+	HasNext     bool
+	Cursors     []string
+}
+
+func (page Page) StartCursor() string {
+	if len(page.Cursors) == 0 {
+		return ""
+	}
+	return page.Cursors[0]
+}
+
+func (page Page) EndCursor() string {
+	if len(page.Cursors) == 0 {
+		return ""
+	}
+	return page.Cursors[len(page.Cursors)-1]
+}
+
+// ParseListQuery reads limit, offset, after, before, sort and field filters
+// (field=value, field~=value) from a query string. Only the given fields may
+// be sorted or filtered on, and createdAt only sorted on, as in GraphQL. With
+// before, limit counts back from the cursor.
+func ParseListQuery(values url.Values, fields []string) (ListQuery, error) {
+	var query ListQuery
+	allowed := map[string]bool{}
+	for _, field := range fields {
+		allowed[field] = true
+	}
+	for key, list := range values {
+		value := list[len(list)-1]
+		switch key {
+		case "limit", "offset":
+			number, err := strconv.Atoi(value)
+			if err != nil || number < 0 || (key == "limit" && number == 0) {
+				return ListQuery{}, BadRequest(fmt.Sprintf("%s must be a positive number", key))
+			}
+			if key == "limit" {
+				query.First = number
+			} else {
+				query.Offset = number
+			}
+		case "after":
+			query.After = value
+		case "before":
+			query.Before = value
+		case "sort":
+			for _, field := range splitList(value) {
+				descending := strings.HasPrefix(field, "-")
+				field = strings.TrimPrefix(field, "-")
+				if !allowed[field] {
+					return ListQuery{}, BadRequest(fmt.Sprintf("cannot sort by %q", field))
+				}
+				query.Sort = append(query.Sort, SortField{Field: field, Descending: descending})
+			}
+		default:
+			field := strings.TrimSuffix(key, "~")
+			if !allowed[field] {
+				return ListQuery{}, BadRequest(fmt.Sprintf("unknown query parameter %q", key))
+			}
+			if field == "createdAt" {
+				return ListQuery{}, BadRequest(fmt.Sprintf("cannot filter by %q", field))
+			}
+			query.Filters = append(query.Filters, Filter{Field: field, Contains: field != key, Values: list})
+		}
+	}
+	sort.Slice(query.Filters, func(i, j int) bool {
+		return query.Filters[i].Field < query.Filters[j].Field
+	})
+	if query.Before != "" && query.After == "" {
+		query.First, query.Last = 0, query.First
+	}
+	return query, nil
+}
+
+// Select applies the query to a list of length items, reading field values
+// through value, and returns the indices of the selected items in order.
+// Every item must expose an "id" field.
+func (query ListQuery) Select(length int, value func(index int, field string) string) ([]int, Page, error) {
+	var indices []int
+	for index := 0; index < length; index++ {
+		if query.matches(index, value) {
+			indices = append(indices, index)
+		}
+	}
+	keys := make(map[int][]string, len(indices))
+	for _, index := range indices {
+		key := make([]string, 0, len(query.Sort)+1)
+		for _, field := range query.Sort {
+			key = append(key, value(index, field.Field))
+		}
+		keys[index] = append(key, value(index, "id"))
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return query.compare(keys[indices[i]], keys[indices[j]]) < 0
+	})
+
+	page := Page{Total: len(indices), Offset: query.Offset}
+	if query.After != "" {
+		after, err := query.decodeCursor(query.After)
+		if err != nil {
+			return nil, Page{}, err
+		}
+		start := sort.Search(len(indices), func(i int) bool {
+			return query.compare(keys[indices[i]], after) > 0
+		})
+		page.HasPrevious = start > 0
+		indices = indices[start:]
+	}
+	if query.Before != "" {
+		before, err := query.decodeCursor(query.Before)
+		if err != nil {
+			return nil, Page{}, err
+		}
+		end := sort.Search(len(indices), func(i int) bool {
+			return query.compare(keys[indices[i]], before) >= 0
+		})
+		page.HasNext = end < len(indices)
+		indices = indices[:end]
+	}
+	if query.Offset > 0 {
+		if query.Offset > len(indices) {
+			query.Offset = len(indices)
+		}
+		page.HasPrevious = true
+		indices = indices[query.Offset:]
+	}
+	if query.First > 0 && len(indices) > query.First {
+		page.HasNext = true
+		indices = indices[:query.First]
+	}
+	if query.Last > 0 && len(indices) > query.Last {
+		page.HasPrevious = true
+		indices = indices[len(indices)-query.Last:]
+	}
+	for _, index := range indices {
+		page.Cursors = append(page.Cursors, encodeCursor(keys[index]))
+	}
+	return indices, page, nil
+}
+
+func (query ListQuery) matches(index int, value func(index int, field string) string) bool {
+	for _, filter := range query.Filters {
+		actual := value(index, filter.Field)
+		matched := false
+		for _, expected := range filter.Values {
+			if filter.Contains {
+				matched = strings.Contains(strings.ToLower(actual), strings.ToLower(expected))
+			} else {
+				matched = actual == expected
+			}
+			if matched {
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func (query ListQuery) compare(a []string, b []string) int {
+	for position := range a {
+		result := strings.Compare(a[position], b[position])
+		if position < len(query.Sort) && query.Sort[position].Descending {
+			result = -result
+		}
+		if result != 0 {
+			return result
+		}
+	}
+	return 0
+}
+
+// sortableTime formats t with a fixed width, so that comparing the strings
+// orders them chronologically.
+func sortableTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000000000Z")
+}
+
+// A cursor is the sort key of an item, so it stays meaningful when the item
+// it came from is deleted, but only for the same sort order.
+func encodeCursor(key []string) string {
+	data, _ := json.Marshal(key)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func (query ListQuery) decodeCursor(cursor string) ([]string, error) {
+	var key []string
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(data, &key)
+	}
+	if err != nil || len(key) != len(query.Sort)+1 {
+		return nil, BadRequest("invalid cursor for this sort order")
+	}
+	return key, nil
+}
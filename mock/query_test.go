@@ -0,0 +1,159 @@
+package mock_test
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+)
+
+var queryFields = []string{"id", "name", "team", "createdAt"}
+
+var queryItems = []map[string]string{
+	{"id": "a", "name": "Alice", "team": "red"},
+	{"id": "b", "name": "Bob", "team": "blue"},
+	{"id": "c", "name": "Carol", "team": "red"},
+	{"id": "d", "name": "Dave", "team": "blue"},
+	{"id": "e", "name": "Eve", "team": "red"},
+}
+
+// selectIds runs a query string against queryItems and returns the ids it
+// selected.
+func selectIds(t *testing.T, raw string) ([]string, mock.Page, error) {
+	t.Helper()
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query, err := mock.ParseListQuery(values, queryFields)
+	if err != nil {
+		return nil, mock.Page{}, err
+	}
+	indices, page, err := query.Select(len(queryItems), func(index int, field string) string {
+		return queryItems[index][field]
+	})
+	if err != nil {
+		return nil, mock.Page{}, err
+	}
+	ids := []string{}
+	for _, index := range indices {
+		ids = append(ids, queryItems[index]["id"])
+	}
+	return ids, page, nil
+}
+
+func TestParseListQuery(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected mock.ListQuery
+	}{
+		{"", mock.ListQuery{}},
+		{"limit=2&offset=3", mock.ListQuery{First: 2, Offset: 3}},
+		{"sort=team,-name", mock.ListQuery{Sort: []mock.SortField{{Field: "team"}, {Field: "name", Descending: true}}}},
+		{"name=Bob&name=Eve", mock.ListQuery{Filters: []mock.Filter{{Field: "name", Values: []string{"Bob", "Eve"}}}}},
+		{"name~=o", mock.ListQuery{Filters: []mock.Filter{{Field: "name", Contains: true, Values: []string{"o"}}}}},
+		{"team=red&name~=e", mock.ListQuery{Filters: []mock.Filter{
+			{Field: "name", Contains: true, Values: []string{"e"}},
+			{Field: "team", Values: []string{"red"}},
+		}}},
+		{"limit=2&after=x", mock.ListQuery{First: 2, After: "x"}},
+		{"limit=2&before=x", mock.ListQuery{Last: 2, Before: "x"}},
+	}
+	for _, test := range tests {
+		values, _ := url.ParseQuery(test.raw)
+		query, err := mock.ParseListQuery(values, queryFields)
+		if err != nil {
+			t.Errorf("%q: %v", test.raw, err)
+			continue
+		}
+		if !reflect.DeepEqual(query, test.expected) {
+			t.Errorf("%q: expected %+v, got %+v", test.raw, test.expected, query)
+		}
+	}
+
+	for _, raw := range []string{"limit=0", "limit=-1", "limit=x", "offset=-1", "sort=unknown", "sort=-unknown", "unknown=1", "unknown~=1", "createdAt=2020-01-03T00:00:00Z", "createdAt~=2020"} {
+		values, _ := url.ParseQuery(raw)
+		_, err := mock.ParseListQuery(values, queryFields)
+		if apiError := mock.AsError(err); err == nil || apiError.Status != http.StatusBadRequest {
+			t.Errorf("%q: expected a 400, got %v", raw, err)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	tests := []struct {
+		raw         string
+		ids         []string
+		total       int
+		hasPrevious bool
+		hasNext     bool
+	}{
+		{"", []string{"a", "b", "c", "d", "e"}, 5, false, false},
+		{"sort=-name", []string{"e", "d", "c", "b", "a"}, 5, false, false},
+		{"sort=team,-name", []string{"d", "b", "e", "c", "a"}, 5, false, false},
+		{"sort=-team", []string{"a", "c", "e", "b", "d"}, 5, false, false},
+		{"team=blue", []string{"b", "d"}, 2, false, false},
+		{"name=bob", []string{}, 0, false, false},
+		{"name~=O", []string{"b", "c"}, 2, false, false},
+		{"name~=ve&name~=ali", []string{"a", "d", "e"}, 3, false, false},
+		{"team=red&name~=e", []string{"a", "e"}, 2, false, false},
+		{"limit=2", []string{"a", "b"}, 5, false, true},
+		{"limit=2&offset=2", []string{"c", "d"}, 5, true, true},
+		{"limit=2&offset=4", []string{"e"}, 5, true, false},
+		{"offset=9", []string{}, 5, true, false},
+		{"sort=-name&limit=2&offset=1", []string{"d", "c"}, 5, true, true},
+	}
+	for _, test := range tests {
+		ids, page, err := selectIds(t, test.raw)
+		if err != nil {
+			t.Errorf("%q: %v", test.raw, err)
+			continue
+		}
+		if !reflect.DeepEqual(ids, test.ids) {
+			t.Errorf("%q: expected %v, got %v", test.raw, test.ids, ids)
+		}
+		if page.Total != test.total || page.HasPrevious != test.hasPrevious || page.HasNext != test.hasNext {
+			t.Errorf("%q: expected total %d, previous %v, next %v, got %+v", test.raw, test.total, test.hasPrevious, test.hasNext, page)
+		}
+		if len(page.Cursors) != len(ids) {
+			t.Errorf("%q: expected %d cursors, got %d", test.raw, len(ids), len(page.Cursors))
+		}
+	}
+}
+
+func TestSelectCursors(t *testing.T) {
+	_, first, _ := selectIds(t, "sort=-team&limit=2")
+	ids, second, err := selectIds(t, "sort=-team&limit=2&after="+first.EndCursor())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, []string{"e", "b"}) || !second.HasPrevious || !second.HasNext {
+		t.Errorf("after: unexpected page %v %+v", ids, second)
+	}
+	ids, last, _ := selectIds(t, "sort=-team&limit=2&after="+second.EndCursor())
+	if !reflect.DeepEqual(ids, []string{"d"}) || !last.HasPrevious || last.HasNext {
+		t.Errorf("after: unexpected last page %v %+v", ids, last)
+	}
+	ids, previous, _ := selectIds(t, "sort=-team&limit=2&before="+last.StartCursor())
+	if !reflect.DeepEqual(ids, []string{"e", "b"}) || !previous.HasPrevious || !previous.HasNext {
+		t.Errorf("before: unexpected page %v %+v", ids, previous)
+	}
+	ids, _, _ = selectIds(t, "sort=-team&after="+first.StartCursor()+"&before="+last.StartCursor())
+	if !reflect.DeepEqual(ids, []string{"c", "e", "b"}) {
+		t.Errorf("after and before: unexpected page %v", ids)
+	}
+
+	for _, raw := range []string{
+		"after=" + first.EndCursor(),
+		"sort=team,name&before=" + first.EndCursor(),
+		"after=not-a-cursor",
+	} {
+		_, _, err := selectIds(t, raw)
+		if apiError := mock.AsError(err); err == nil || apiError.Status != http.StatusBadRequest || !strings.Contains(apiError.Message, "cursor") {
+			t.Errorf("%q: expected an invalid cursor error, got %v", raw, err)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package rest
+
+import (
+	"encoding/json"
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/gorilla/mux"
+
+	"net/http"
+)
+
+func ArticleCreateEndpoint(response http.ResponseWriter, request *http.Request) {
+	response.Header().Add("content-type", "application/json")
+
+	var article mock.Article
+	token, ok := authenticated(response, request)
+	if !ok {
+		return
+	}
+	err := mock.DecodeBody(request, &article)
+	if err != nil {
+		mock.WriteError(response, err)
+		return
+	}
+
+	article, err = mock.CreateArticle(token, article)
+
+	if err != nil {
+		mock.WriteError(response, err)
+		return
+	}
+
+	response.WriteHeader(http.StatusCreated)
+	json.NewEncoder(response).Encode(article)
+}
+
+func ArticleRetrieveAllEndpoint(response http.ResponseWriter, request *http.Request) {
+	response.Header().Add("content-type", "application/json")
+	query, err := mock.ParseListQuery(request.URL.Query(), mock.ArticleFields)
+	if err != nil {
+		mock.WriteError(response, err)
+		return
+	}
+	articles, page, err := mock.ListArticles(query)
+	if err != nil {
+		mock.WriteError(response, err)
+		return
+	}
+	writePageHeaders(response, request, query, page)
+	json.NewEncoder(response).Encode(articles)
+}
+
+func ArticleRetrieveEndpoint(response http.ResponseWriter, request *http.Request) {
+	response.Header().Add("content-type", "application/json")
+	params := mux.Vars(request)
+	article, err := mock.Articles.Get(params["id"])
+	if err != nil {
+		mock.WriteError(response, err)
+		return
+	}
+	json.NewEncoder(response).Encode(article)
+}
+
+func ArticleUpdateEndpoint(response http.ResponseWriter, request *http.Request) {
+	response.Header().Add("content-type", "application/json")
+	var changes mock.Article
+	params := mux.Vars(request)
+	token, ok := authenticated(response, request)
+	if !ok {
+		return
+	}
+	err := mock.DecodeBody(request, &changes)
+	if err != nil {
+		mock.WriteError(response, err)
+		return
+	}
+	_, err = mock.UpdateArticle(params["id"], token, changes)
+	if err != nil {
+		mock.WriteError(response, err)
+		return
+	}
+	articles, _ := mock.Articles.All()
+	json.NewEncoder(response).Encode(articles)
+}
+
+func ArticleDeleteEndpoint(response http.ResponseWriter, request *http.Request) {
+	response.Header().Add("content-type", "application/json")
+	params := mux.Vars(request)
+	token, ok := authenticated(response, request)
+	if !ok {
+		return
+	}
+	err := mock.DeleteArticle(params["id"], token)
+	if err != nil {
+		mock.WriteError(response, err)
+		return
+	}
+	articles, _ := mock.Articles.All()
+	json.NewEncoder(response).Encode(articles)
+}
@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"encoding/json"
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+func AuthorRetrieveAllEndpoint(response http.ResponseWriter, request *http.Request) {
+	response.Header().Add("content-type", "application/json")
+	query, err := mock.ParseListQuery(request.URL.Query(), mock.AuthorFields)
+	if err != nil {
+		mock.WriteError(response, err)
+		return
+	}
+	authors, page, err := mock.ListAuthors(query)
+	if err != nil {
+		mock.WriteError(response, err)
+		return
+	}
+	writePageHeaders(response, request, query, page)
+	json.NewEncoder(response).Encode(mock.PublicAuthors(authors))
+}
+
+func AuthorRetrieveEndpoint(response http.ResponseWriter, request *http.Request) {
+	response.Header().Add("content-type", "application/json")
+	params := mux.Vars(request)
+	author, err := mock.Authors.Get(params["id"])
+	if err != nil {
+		mock.WriteError(response, err)
+		return
+	}
+	json.NewEncoder(response).Encode(author.Public())
+}
+
+func AuthorUpdateEndpoint(response http.ResponseWriter, request *http.Request) {
+	response.Header().Add("content-type", "application/json")
+	var changes mock.Author
+	params := mux.Vars(request)
+	token, ok := authenticated(response, request)
+	if !ok {
+		return
+	}
+	err := mock.DecodeBody(request, &changes)
+	if err != nil {
+		mock.WriteError(response, err)
+		return
+	}
+	_, err = mock.UpdateAuthor(params["id"], token, changes)
+	if err != nil {
+		mock.WriteError(response, err)
+		return
+	}
+	authors, _ := mock.Authors.All()
+	json.NewEncoder(response).Encode(mock.PublicAuthors(authors))
+}
+
+func AuthorDeleteEndpoint(response http.ResponseWriter, request *http.Request) {
+	response.Header().Add("content-type", "application/json")
+	params := mux.Vars(request)
+	token, ok := authenticated(response, request)
+	if !ok {
+		return
+	}
+	err := mock.DeleteAuthor(params["id"], token)
+	if err != nil {
+		mock.WriteError(response, err)
+		return
+	}
+	authors, _ := mock.Authors.All()
+	json.NewEncoder(response).Encode(mock.PublicAuthors(authors))
+}
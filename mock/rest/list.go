@@ -0,0 +1,59 @@
+package rest
+
+import (
+	"fmt"
+	"github.com/Bone1289/go-web-example/mock"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// writePageHeaders reports the total number of matches in X-Total-Count and,
+// when the request was limited, links to the neighbouring pages. Requests
+// that paged by offset get offset links; all others get cursor links.
+func writePageHeaders(response http.ResponseWriter, request *http.Request, query mock.ListQuery, page mock.Page) {
+	response.Header().Set("X-Total-Count", strconv.Itoa(page.Total))
+	limit := query.First + query.Last
+	if limit == 0 {
+		return
+	}
+	byOffset := request.URL.Query().Get("offset") != ""
+	var links []string
+	link := func(rel string, set map[string]string) {
+		values := request.URL.Query()
+		for _, key := range []string{"offset", "after", "before"} {
+			values.Del(key)
+		}
+		values.Set("limit", strconv.Itoa(limit))
+		for key, value := range set {
+			values.Set(key, value)
+		}
+		target := url.URL{Path: request.URL.Path, RawQuery: values.Encode()}
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, target.String(), rel))
+	}
+	link("first", nil)
+	if byOffset {
+		if page.HasPrevious {
+			previous := page.Offset - limit
+			if previous < 0 {
+				previous = 0
+			}
+			link("prev", map[string]string{"offset": strconv.Itoa(previous)})
+		}
+		if page.HasNext {
+			link("next", map[string]string{"offset": strconv.Itoa(page.Offset + limit)})
+		}
+		if page.Total > 0 {
+			link("last", map[string]string{"offset": strconv.Itoa((page.Total - 1) / limit * limit)})
+		}
+	} else {
+		if page.HasPrevious && len(page.Cursors) > 0 {
+			link("prev", map[string]string{"before": page.StartCursor()})
+		}
+		if page.HasNext && len(page.Cursors) > 0 {
+			link("next", map[string]string{"after": page.EndCursor()})
+		}
+	}
+	response.Header().Set("Link", strings.Join(links, ", "))
+}
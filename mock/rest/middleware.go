@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"github.com/Bone1289/go-web-example/mock"
+	"net/http"
+)
+
+// ValidateMiddleware only lets requests with a valid "Authorization: Bearer"
+// token through, with the token's claims stored in the request context. Every
+// other request is rejected with a 401 and a WWW-Authenticate challenge.
+func ValidateMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		token, err := mock.BearerToken(request)
+		if err != nil {
+			mock.WriteUnauthorized(response, err)
+			return
+		}
+		claims, err := mock.Authenticate(token)
+		if err != nil {
+			mock.WriteUnauthorized(response, err)
+			return
+		}
+		next(response, request.WithContext(mock.WithClaims(request.Context(), claims, nil)))
+	})
+}
+
+// RequirePermission authenticates like ValidateMiddleware and then rejects
+// callers whose role does not grant permission with a 403.
+func RequirePermission(permission mock.Permission, next http.HandlerFunc) http.HandlerFunc {
+	return ValidateMiddleware(func(response http.ResponseWriter, request *http.Request) {
+		claims, ok := authenticated(response, request)
+		if !ok {
+			return
+		}
+		err := mock.Require(claims, permission)
+		if err != nil {
+			mock.WriteError(response, err)
+			return
+		}
+		next(response, request)
+	})
+}
+
+// authenticated returns the claims ValidateMiddleware stored in the request
+// context. A handler mounted without the middleware has none, in which case
+// the request is rejected with a 401.
+func authenticated(response http.ResponseWriter, request *http.Request) (mock.CustomJWTClaims, bool) {
+	claims, err := mock.ClaimsFrom(request.Context())
+	if err != nil {
+		mock.WriteUnauthorized(response, err)
+		return mock.CustomJWTClaims{}, false
+	}
+	return claims, true
+}
@@ -0,0 +1,32 @@
+package rest
+
+import (
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+func RootEndpoint(response http.ResponseWriter, request *http.Request) {
+	response.Header().Add("content-type", "application/json")
+	response.Write([]byte(`{ "message": "Hello World" }`))
+}
+
+// Register mounts the REST API, including the shared /login, /token/refresh,
+// /logout, /author and JWKS endpoints, on router.
+func Register(router *mux.Router) {
+	router.HandleFunc("/", RootEndpoint).Methods("GET")
+	router.HandleFunc("/login", mock.LoginEndpoint).Methods("POST")
+	router.HandleFunc("/token/refresh", mock.RefreshEndpoint).Methods("POST")
+	router.HandleFunc("/logout", mock.LogoutEndpoint).Methods("POST")
+	router.HandleFunc("/.well-known/jwks.json", mock.JWKSEndpoint).Methods("GET")
+	router.HandleFunc("/author", mock.RegisterEndpoint).Methods("POST")
+	router.HandleFunc("/authors", AuthorRetrieveAllEndpoint).Methods("GET")
+	router.HandleFunc("/author/{id}", AuthorRetrieveEndpoint).Methods("GET")
+	router.HandleFunc("/author/{id}", ValidateMiddleware(AuthorUpdateEndpoint)).Methods("PUT")
+	router.HandleFunc("/author/{id}", ValidateMiddleware(AuthorDeleteEndpoint)).Methods("DELETE")
+	router.HandleFunc("/article", RequirePermission(mock.PermCreateArticle, ArticleCreateEndpoint)).Methods("POST")
+	router.HandleFunc("/articles", ArticleRetrieveAllEndpoint).Methods("GET")
+	router.HandleFunc("/article/{id}", ArticleRetrieveEndpoint).Methods("GET")
+	router.HandleFunc("/article/{id}", ValidateMiddleware(ArticleUpdateEndpoint)).Methods("PUT")
+	router.HandleFunc("/article/{id}", ValidateMiddleware(ArticleDeleteEndpoint)).Methods("DELETE")
+}
@@ -0,0 +1,34 @@
+package mock
+
+import (
+	"time"
+)
+
+var SeedAuthors = []Author{
+	{
+		Id:        "author-1",
+		Firstname: "Nicolas",
+		Lastname:  "Raboy",
+		Username:  "nraboy",
+		Password:  "$2a$10$0OtFx9DSi5x.bnjx28f4Xu1pkURjYVnTvgFnvoxIdyXambjSyLQhW",
+		CreatedAt: time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Id:        "author-2",
+		Firstname: "Maria",
+		Lastname:  "Raboy",
+		Username:  "mraboy",
+		Password:  "$2a$10$0OtFx9DSi5x.bnjx28f4Xu1pkURjYVnTvgFnvoxIdyXambjSyLQhW",
+		CreatedAt: time.Date(2020, time.January, 2, 0, 0, 0, 0, time.UTC),
+	},
+}
+
+var SeedArticles = []Article{
+	{
+		Id:        "article-1",
+		Author:    "author-1",
+		Title:     "This is an Example Article",
+		Content:   "This is some sample content",
+		CreatedAt: time.Date(2020, time.January, 3, 0, 0, 0, 0, time.UTC),
+	},
+}
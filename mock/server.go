@@ -0,0 +1,73 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Flusher is implemented by stores that can write their state to durable
+// storage on demand.
+type Flusher interface {
+	Flush() error
+}
+
+// Flush asks every store that supports it to persist its current state.
+func Flush() error {
+	for _, store := range []interface{}{Authors, Articles} {
+		if flusher, ok := store.(Flusher); ok {
+			if err := flusher.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Serve runs handler on config.Addr until SIGINT or SIGTERM arrives, then
+// stops accepting connections and waits up to config.ShutdownTimeout for
+// in-flight requests to finish. It returns an error when the address cannot
+// be bound, the server fails, or draining does not complete in time.
+func Serve(config Config, handler http.Handler) error {
+	server := &http.Server{
+		Addr:         config.Addr,
+		Handler:      handler,
+		ReadTimeout:  config.ReadTimeout.Duration,
+		WriteTimeout: config.WriteTimeout.Duration,
+		IdleTimeout:  config.IdleTimeout.Duration,
+	}
+	listener, err := net.Listen("tcp", config.Addr)
+	if err != nil {
+		return err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	failed := make(chan error, 1)
+	go func() {
+		failed <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-failed:
+		return err
+	case received := <-signals:
+		fmt.Printf("Received %v, shutting down...\n", received)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout.Duration)
+	defer cancel()
+	err = server.Shutdown(ctx)
+	if config.FlushOnShutdown {
+		if flushErr := Flush(); err == nil {
+			err = flushErr
+		}
+	}
+	return err
+}
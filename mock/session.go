@@ -0,0 +1,253 @@
+package mock
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+var RefreshTokenLifetime = 30 * 24 * time.Hour
+
+// Tokens is what a successful login or refresh returns. The refresh token is
+// single use: refreshing hands out a new one and retires the old.
+type Tokens struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+// refreshSession is the server-side record of a refresh token. Every token
+// obtained by rotating belongs to the family started at login, so presenting
+// a retired token again, a sign it was stolen, revokes the whole family.
+type refreshSession struct {
+	author  string
+	family  string
+	expires time.Time
+	used    bool
+}
+
+// Refresh tokens are only kept in memory, so a restart logs everyone out of
+// their refresh sessions. Revoked access token ids are kept in memory too,
+// unless the file store backend gives them a path to be saved to; with the
+// memory backend a restart makes logged out access tokens valid again until
+// they expire. Access tokens issued before resetAt belong to a dataset that
+// was reset and are revoked as well.
+var sessions = struct {
+	sync.Mutex
+	refresh map[string]*refreshSession
+	revoked map[string]time.Time
+	resetAt time.Time
+	path    string
+}{
+	refresh: map[string]*refreshSession{},
+	revoked: map[string]time.Time{},
+}
+
+func randomToken() string {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// NewTokens issues an access token and a refresh token starting a new family.
+func NewTokens(author Author) (Tokens, error) {
+	return issueTokens(author, randomToken())
+}
+
+func issueTokens(author Author, family string) (Tokens, error) {
+	token, err := NewToken(author)
+	if err != nil {
+		return Tokens{}, err
+	}
+	refreshToken := randomToken()
+	sessions.Lock()
+	defer sessions.Unlock()
+	pruneSessions()
+	sessions.refresh[refreshToken] = &refreshSession{
+		author:  author.Id,
+		family:  family,
+		expires: time.Now().Add(RefreshTokenLifetime),
+	}
+	return Tokens{Token: token, RefreshToken: refreshToken}, nil
+}
+
+// Refresh exchanges a refresh token for a new pair of tokens.
+func Refresh(refreshToken string) (Tokens, error) {
+	sessions.Lock()
+	session, ok := sessions.refresh[refreshToken]
+	if ok && session.used {
+		revokeFamily(session.family)
+	}
+	valid := ok && !session.used && time.Now().Before(session.expires)
+	if valid {
+		session.used = true
+	}
+	sessions.Unlock()
+	if !valid {
+		return Tokens{}, ErrInvalidToken
+	}
+	author, err := Authors.Get(session.author)
+	if err == ErrNotFound {
+		return Tokens{}, ErrInvalidToken
+	} else if err != nil {
+		return Tokens{}, err
+	}
+	return issueTokens(author, session.family)
+}
+
+// Logout revokes the access token described by claims and, when given, the
+// family of refreshToken. A refresh token of another author is ignored.
+func Logout(claims CustomJWTClaims, refreshToken string) error {
+	sessions.Lock()
+	defer sessions.Unlock()
+	pruneSessions()
+	if session, ok := sessions.refresh[refreshToken]; ok && session.author == claims.Id {
+		revokeFamily(session.family)
+	}
+	if claims.ID != "" && claims.ExpiresAt != nil {
+		sessions.revoked[claims.ID] = claims.ExpiresAt.Time
+	}
+	return saveRevocations()
+}
+
+// pruneSessions forgets expired refresh tokens and revoked access tokens that
+// have expired anyway. The caller holds the lock.
+func pruneSessions() {
+	now := time.Now()
+	// ValidateJWT accepts tokens up to ClockSkew past their expiry, so they
+	// stay revoked until then.
+	for id, expires := range sessions.revoked {
+		if now.After(expires.Add(ClockSkew)) {
+			delete(sessions.revoked, id)
+		}
+	}
+	for token, session := range sessions.refresh {
+		if now.After(session.expires) {
+			delete(sessions.refresh, token)
+		}
+	}
+}
+
+// revokeFamily drops every refresh token of family. The caller holds the lock.
+func revokeFamily(family string) {
+	for token, session := range sessions.refresh {
+		if session.family == family {
+			delete(sessions.refresh, token)
+		}
+	}
+}
+
+// IsRevoked reports whether the access token described by claims was logged
+// out or issued before the stores were last reset.
+func IsRevoked(claims CustomJWTClaims) bool {
+	sessions.Lock()
+	defer sessions.Unlock()
+	if _, revoked := sessions.revoked[claims.ID]; revoked {
+		return true
+	}
+	// iat only has whole seconds, so tokens issued in the second of the reset
+	// are let through rather than rejecting the ones issued right after it.
+	return claims.IssuedAt != nil && claims.IssuedAt.Time.Before(sessions.resetAt.Truncate(time.Second))
+}
+
+// resetSessions forgets every refresh token and revoked token id, and revokes
+// the access tokens issued so far instead.
+func resetSessions() error {
+	sessions.Lock()
+	defer sessions.Unlock()
+	sessions.refresh = map[string]*refreshSession{}
+	sessions.revoked = map[string]time.Time{}
+	sessions.resetAt = time.Now()
+	return saveRevocations()
+}
+
+// revocations is the document revoked access tokens are saved as.
+type revocations struct {
+	Tokens  map[string]time.Time `json:"tokens"`
+	ResetAt time.Time            `json:"resetAt"`
+}
+
+// openRevocations replaces the revoked access tokens with the ones saved at
+// path, and saves them there from now on. An empty path keeps them in memory.
+func openRevocations(path string) error {
+	sessions.Lock()
+	defer sessions.Unlock()
+	sessions.path = path
+	sessions.revoked = map[string]time.Time{}
+	sessions.resetAt = time.Time{}
+	if path == "" {
+		return nil
+	}
+	var saved revocations
+	if err := readJSONFile(path, &saved); os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if saved.Tokens != nil {
+		sessions.revoked = saved.Tokens
+	}
+	sessions.resetAt = saved.ResetAt
+	pruneSessions()
+	return nil
+}
+
+// saveRevocations writes the revoked access tokens to the file store, if
+// there is one. The caller holds the lock.
+func saveRevocations() error {
+	if sessions.path == "" {
+		return nil
+	}
+	return writeJSONFile(sessions.path, revocations{Tokens: sessions.revoked, ResetAt: sessions.resetAt})
+}
+
+func RefreshEndpoint(response http.ResponseWriter, request *http.Request) {
+	response.Header().Add("content-type", "application/json")
+	var data Tokens
+	err := DecodeBody(request, &data)
+	if err != nil {
+		WriteError(response, err)
+		return
+	}
+	tokens, err := Refresh(data.RefreshToken)
+	if err != nil {
+		WriteError(response, err)
+		return
+	}
+	json.NewEncoder(response).Encode(tokens)
+}
+
+// LogoutEndpoint revokes the bearer token of the request, plus the refresh
+// token in the optional body.
+func LogoutEndpoint(response http.ResponseWriter, request *http.Request) {
+	response.Header().Add("content-type", "application/json")
+	token, err := BearerToken(request)
+	if err != nil {
+		WriteUnauthorized(response, err)
+		return
+	}
+	claims, err := Authenticate(token)
+	if err != nil {
+		WriteUnauthorized(response, err)
+		return
+	}
+	var data Tokens
+	if request.ContentLength != 0 {
+		err = DecodeBody(request, &data)
+		if err != nil {
+			WriteError(response, err)
+			return
+		}
+	}
+	err = Logout(claims, data.RefreshToken)
+	if err != nil {
+		WriteError(response, err)
+		return
+	}
+	response.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,452 @@
+package mock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var ErrNotFound = errors.New("record not found")
+
+// Authors and Articles are the stores every endpoint and resolver reads from.
+// Binaries assign them once at startup, usually from OpenStores.
+var Authors AuthorStore
+var Articles ArticleStore
+
+type AuthorStore interface {
+	All() ([]Author, error)
+	Get(id string) (Author, error)
+	// GetMany returns the authors with the given ids in a single lookup.
+	// Unknown ids are skipped rather than reported.
+	GetMany(ids []string) ([]Author, error)
+	GetByUsername(username string) (Author, error)
+	Create(author Author) error
+	Update(id string, update func(author *Author) error) (Author, error)
+	Delete(id string) error
+	Replace(authors []Author) error
+}
+
+type ArticleStore interface {
+	All() ([]Article, error)
+	Get(id string) (Article, error)
+	Create(article Article) error
+	Update(id string, update func(article *Article) error) (Article, error)
+	Delete(id string) error
+	Replace(articles []Article) error
+}
+
+// OpenStores builds the author and article stores for the given kind.
+// "memory" keeps everything in process, "file" persists each store as a
+// JSON document inside dir so data survives restarts, along with the access
+// tokens revoked by logging out.
+func OpenStores(kind string, dir string) (AuthorStore, ArticleStore, error) {
+	switch kind {
+	case "memory":
+		if err := openRevocations(""); err != nil {
+			return nil, nil, err
+		}
+		return NewMemoryAuthorStore(SeedAuthors), NewMemoryArticleStore(SeedArticles), nil
+	case "file":
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, nil, err
+		}
+		if err := openRevocations(filepath.Join(dir, "revoked.json")); err != nil {
+			return nil, nil, err
+		}
+		authorStore, err := NewFileAuthorStore(filepath.Join(dir, "authors.json"), SeedAuthors)
+		if err != nil {
+			return nil, nil, err
+		}
+		articleStore, err := NewFileArticleStore(filepath.Join(dir, "articles.json"), SeedArticles)
+		if err != nil {
+			return nil, nil, err
+		}
+		return authorStore, articleStore, nil
+	}
+	return nil, nil, fmt.Errorf("unknown store %q", kind)
+}
+
+// MemoryAuthorStore guards its slice with a read/write lock so handlers
+// running on concurrent net/http goroutines never observe a half-applied
+// change. Update runs the caller's change while holding the lock, which makes
+// read-modify-write sequences atomic.
+type MemoryAuthorStore struct {
+	mutex   sync.RWMutex
+	authors []Author
+}
+
+func NewMemoryAuthorStore(seed []Author) *MemoryAuthorStore {
+	return &MemoryAuthorStore{authors: append([]Author{}, seed...)}
+}
+
+func (store *MemoryAuthorStore) All() ([]Author, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	return append([]Author{}, store.authors...), nil
+}
+
+func (store *MemoryAuthorStore) Get(id string) (Author, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	for _, author := range store.authors {
+		if author.Id == id {
+			return author, nil
+		}
+	}
+	return Author{}, ErrNotFound
+}
+
+func (store *MemoryAuthorStore) GetMany(ids []string) ([]Author, error) {
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	var authors []Author
+	for _, author := range store.authors {
+		if wanted[author.Id] {
+			authors = append(authors, author)
+		}
+	}
+	return authors, nil
+}
+
+func (store *MemoryAuthorStore) GetByUsername(username string) (Author, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	for _, author := range store.authors {
+		if author.Username == username {
+			return author, nil
+		}
+	}
+	return Author{}, ErrNotFound
+}
+
+func (store *MemoryAuthorStore) Create(author Author) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	for _, existing := range store.authors {
+		if existing.Id == author.Id || existing.Username == author.Username {
+			return ErrConflict
+		}
+	}
+	store.authors = append(store.authors, author)
+	return nil
+}
+
+func (store *MemoryAuthorStore) Update(id string, update func(author *Author) error) (Author, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	for index := range store.authors {
+		if store.authors[index].Id == id {
+			author := store.authors[index]
+			if err := update(&author); err != nil {
+				return Author{}, err
+			}
+			for other, existing := range store.authors {
+				if other != index && existing.Username == author.Username {
+					return Author{}, ErrConflict
+				}
+			}
+			store.authors[index] = author
+			return author, nil
+		}
+	}
+	return Author{}, ErrNotFound
+}
+
+func (store *MemoryAuthorStore) Delete(id string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	for index, author := range store.authors {
+		if author.Id == id {
+			store.authors = append(store.authors[:index], store.authors[index+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// Replace swaps the whole contents of the store, which is how fixtures are
+// (re)loaded.
+func (store *MemoryAuthorStore) Replace(authors []Author) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.authors = append([]Author{}, authors...)
+	return nil
+}
+
+type MemoryArticleStore struct {
+	mutex    sync.RWMutex
+	articles []Article
+}
+
+func NewMemoryArticleStore(seed []Article) *MemoryArticleStore {
+	return &MemoryArticleStore{articles: append([]Article{}, seed...)}
+}
+
+func (store *MemoryArticleStore) All() ([]Article, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	return append([]Article{}, store.articles...), nil
+}
+
+func (store *MemoryArticleStore) Get(id string) (Article, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	for _, article := range store.articles {
+		if article.Id == id {
+			return article, nil
+		}
+	}
+	return Article{}, ErrNotFound
+}
+
+func (store *MemoryArticleStore) Create(article Article) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	for _, existing := range store.articles {
+		if existing.Id == article.Id {
+			return ErrConflict
+		}
+	}
+	store.articles = append(store.articles, article)
+	return nil
+}
+
+func (store *MemoryArticleStore) Update(id string, update func(article *Article) error) (Article, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	for index := range store.articles {
+		if store.articles[index].Id == id {
+			article := store.articles[index]
+			if err := update(&article); err != nil {
+				return Article{}, err
+			}
+			store.articles[index] = article
+			return article, nil
+		}
+	}
+	return Article{}, ErrNotFound
+}
+
+func (store *MemoryArticleStore) Delete(id string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	for index, article := range store.articles {
+		if article.Id == id {
+			store.articles = append(store.articles[:index], store.articles[index+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (store *MemoryArticleStore) Replace(articles []Article) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.articles = append([]Article{}, articles...)
+	return nil
+}
+
+// FileAuthorStore serves reads from memory and rewrites its JSON file after
+// every successful change. Writes are serialized so the file always reflects
+// the latest change rather than whichever goroutine saved last.
+type FileAuthorStore struct {
+	*MemoryAuthorStore
+	mutex sync.Mutex
+	path  string
+}
+
+func NewFileAuthorStore(path string, seed []Author) (*FileAuthorStore, error) {
+	var authors []Author
+	if err := readJSONFile(path, &authors); os.IsNotExist(err) {
+		authors = seed
+	} else if err != nil {
+		return nil, err
+	}
+	store := &FileAuthorStore{MemoryAuthorStore: NewMemoryAuthorStore(authors), path: path}
+	return store, store.save()
+}
+
+func (store *FileAuthorStore) Create(author Author) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	return store.apply(func() error {
+		return store.MemoryAuthorStore.Create(author)
+	})
+}
+
+func (store *FileAuthorStore) Update(id string, update func(author *Author) error) (Author, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	var updated Author
+	err := store.apply(func() (err error) {
+		updated, err = store.MemoryAuthorStore.Update(id, update)
+		return err
+	})
+	if err != nil {
+		return Author{}, err
+	}
+	return updated, nil
+}
+
+func (store *FileAuthorStore) Delete(id string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	return store.apply(func() error {
+		return store.MemoryAuthorStore.Delete(id)
+	})
+}
+
+func (store *FileAuthorStore) Replace(authors []Author) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	return store.apply(func() error {
+		return store.MemoryAuthorStore.Replace(authors)
+	})
+}
+
+func (store *FileAuthorStore) Flush() error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	return store.save()
+}
+
+// apply makes change in memory and saves it. When the file cannot be written
+// the change is rolled back, so a failed write never leaves a record that
+// exists in memory only. The caller holds the mutex.
+func (store *FileAuthorStore) apply(change func() error) error {
+	previous, _ := store.MemoryAuthorStore.All()
+	if err := change(); err != nil {
+		return err
+	}
+	if err := store.save(); err != nil {
+		store.MemoryAuthorStore.Replace(previous)
+		return err
+	}
+	return nil
+}
+
+func (store *FileAuthorStore) save() error {
+	authors, _ := store.MemoryAuthorStore.All()
+	return writeJSONFile(store.path, authors)
+}
+
+type FileArticleStore struct {
+	*MemoryArticleStore
+	mutex sync.Mutex
+	path  string
+}
+
+func NewFileArticleStore(path string, seed []Article) (*FileArticleStore, error) {
+	var articles []Article
+	if err := readJSONFile(path, &articles); os.IsNotExist(err) {
+		articles = seed
+	} else if err != nil {
+		return nil, err
+	}
+	store := &FileArticleStore{MemoryArticleStore: NewMemoryArticleStore(articles), path: path}
+	return store, store.save()
+}
+
+func (store *FileArticleStore) Create(article Article) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	return store.apply(func() error {
+		return store.MemoryArticleStore.Create(article)
+	})
+}
+
+func (store *FileArticleStore) Update(id string, update func(article *Article) error) (Article, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	var updated Article
+	err := store.apply(func() (err error) {
+		updated, err = store.MemoryArticleStore.Update(id, update)
+		return err
+	})
+	if err != nil {
+		return Article{}, err
+	}
+	return updated, nil
+}
+
+func (store *FileArticleStore) Delete(id string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	return store.apply(func() error {
+		return store.MemoryArticleStore.Delete(id)
+	})
+}
+
+func (store *FileArticleStore) Replace(articles []Article) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	return store.apply(func() error {
+		return store.MemoryArticleStore.Replace(articles)
+	})
+}
+
+func (store *FileArticleStore) Flush() error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	return store.save()
+}
+
+func (store *FileArticleStore) apply(change func() error) error {
+	previous, _ := store.MemoryArticleStore.All()
+	if err := change(); err != nil {
+		return err
+	}
+	if err := store.save(); err != nil {
+		store.MemoryArticleStore.Replace(previous)
+		return err
+	}
+	return nil
+}
+
+func (store *FileArticleStore) save() error {
+	articles, _ := store.MemoryArticleStore.All()
+	return writeJSONFile(store.path, articles)
+}
+
+func readJSONFile(path string, value interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
+
+// writeJSONFile writes through a temporary file, synced to disk before it is
+// renamed into place, so a crash mid-write never leaves a truncated store
+// behind.
+func writeJSONFile(path string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	temp := path + ".tmp"
+	file, err := os.OpenFile(temp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp, path)
+}
@@ -0,0 +1,53 @@
+package mock_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+)
+
+// TestFileStoreRollback makes saving fail by putting a directory where the
+// store file goes, after which no change may stay behind in memory.
+func TestFileStoreRollback(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "authors.json")
+	existing := mock.Author{Id: "existing", Username: "existing"}
+	store, err := mock.NewFileAuthorStore(path, []mock.Author{existing})
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(path)
+	if err := os.MkdirAll(filepath.Join(path, "blocked"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Create(mock.Author{Id: "new", Username: "new"}); err == nil {
+		t.Error("create succeeded although the store could not be saved")
+	}
+	if _, err := store.Get("new"); err != mock.ErrNotFound {
+		t.Errorf("failed create left the author behind: %v", err)
+	}
+	_, err = store.Update("existing", func(author *mock.Author) error {
+		author.Username = "changed"
+		return nil
+	})
+	if err == nil {
+		t.Error("update succeeded although the store could not be saved")
+	}
+	if author, _ := store.Get("existing"); author.Username != "existing" {
+		t.Errorf("failed update was kept: %+v", author)
+	}
+	if err := store.Delete("existing"); err == nil {
+		t.Error("delete succeeded although the store could not be saved")
+	}
+	if _, err := store.Get("existing"); err != nil {
+		t.Errorf("failed delete removed the author: %v", err)
+	}
+}
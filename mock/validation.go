@@ -0,0 +1,54 @@
+package mock
+
+import (
+	"fmt"
+	"gopkg.in/go-playground/validator.v9"
+	"reflect"
+	"strings"
+)
+
+// newValidator returns a validator that reports fields by their JSON names,
+// so validation details line up with the request bodies and GraphQL inputs.
+func newValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return validate
+}
+
+func fieldErrors(validationErrors validator.ValidationErrors) []FieldError {
+	var details []FieldError
+	for _, fieldError := range validationErrors {
+		details = append(details, FieldError{
+			Field:   fieldError.Field(),
+			Rule:    fieldError.Tag(),
+			Param:   fieldError.Param(),
+			Message: fieldMessage(fieldError),
+		})
+	}
+	return details
+}
+
+func fieldMessage(fieldError validator.FieldError) string {
+	switch fieldError.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", fieldError.Field())
+	case "gte":
+		if fieldError.Kind() == reflect.String {
+			return fmt.Sprintf("%s must be at least %s characters long", fieldError.Field(), fieldError.Param())
+		}
+		return fmt.Sprintf("%s must be at least %s", fieldError.Field(), fieldError.Param())
+	case "uuid":
+		return fmt.Sprintf("%s must be a valid UUID", fieldError.Field())
+	case "isdefault":
+		return fmt.Sprintf("%s must not be set", fieldError.Field())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", fieldError.Field(), strings.Join(strings.Fields(fieldError.Param()), ", "))
+	}
+	return fmt.Sprintf("%s failed the %s rule", fieldError.Field(), fieldError.Tag())
+}
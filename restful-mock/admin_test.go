@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/Bone1289/go-web-example/mock/mocktest"
+)
+
+// adminCall sends a bodiless /admin request with the given X-Admin-Key.
+func adminCall(t *testing.T, method string, url string, key string, out interface{}) int {
+	request, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "" {
+		request.Header.Set("X-Admin-Key", key)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	if out != nil {
+		json.NewDecoder(response.Body).Decode(out)
+	}
+	return response.StatusCode
+}
+
+func TestAdminKey(t *testing.T) {
+	server := newServer(t, "memory")
+	key := mock.AdminKey
+	t.Cleanup(func() { mock.AdminKey = key })
+
+	mock.AdminKey = ""
+	if status := adminCall(t, "POST", server.URL+"/admin/reset", "anything", nil); status != http.StatusForbidden {
+		t.Errorf("disabled admin endpoints: expected status 403, got %d", status)
+	}
+	mock.AdminKey = "admin-key"
+	if status := adminCall(t, "POST", server.URL+"/admin/reset", "", nil); status != http.StatusUnauthorized {
+		t.Errorf("missing admin key: expected status 401, got %d", status)
+	}
+	if status := adminCall(t, "POST", server.URL+"/admin/reset", "wrong-key", nil); status != http.StatusUnauthorized {
+		t.Errorf("wrong admin key: expected status 401, got %d", status)
+	}
+	if status := adminCall(t, "POST", server.URL+"/admin/reset", "admin-key", nil); status != http.StatusOK {
+		t.Errorf("valid admin key: expected status 200, got %d", status)
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	server := newServer(t, "memory")
+	key := mock.AdminKey
+	t.Cleanup(func() { mock.AdminKey = key })
+	mock.AdminKey = "admin-key"
+
+	_, token := mocktest.CreateAndLogin(t, server, "snapshot")
+	var article mock.Article
+	mocktest.Call(t, "POST", server.URL+"/article", token, mock.Article{Title: "title", Content: "content"}, &article)
+	var tokens mock.Tokens
+	mocktest.Call(t, "POST", server.URL+"/login", "", mock.Author{Username: "snapshot", Password: "secret"}, &tokens)
+
+	var counts map[string]interface{}
+	if status := adminCall(t, "POST", server.URL+"/admin/snapshots/before", "admin-key", &counts); status != http.StatusCreated {
+		t.Fatalf("create snapshot: expected status 201, got %d", status)
+	}
+	if counts["authors"] != float64(len(mock.SeedAuthors)+1) || counts["articles"] != float64(len(mock.SeedArticles)+1) {
+		t.Errorf("unexpected snapshot counts %v", counts)
+	}
+	var names []string
+	adminCall(t, "GET", server.URL+"/admin/snapshots", "admin-key", &names)
+	if len(names) != 1 || names[0] != "before" {
+		t.Errorf("unexpected snapshots %v", names)
+	}
+
+	mocktest.Call(t, "DELETE", server.URL+"/article/"+article.Id, token, nil, nil)
+	if _, err := mock.Articles.Get(article.Id); err != mock.ErrNotFound {
+		t.Fatalf("article was not deleted: %v", err)
+	}
+	if status := adminCall(t, "POST", server.URL+"/admin/snapshots/before/restore", "admin-key", nil); status != http.StatusOK {
+		t.Fatalf("restore snapshot: expected status 200, got %d", status)
+	}
+	if _, err := mock.Articles.Get(article.Id); err != nil {
+		t.Errorf("restore did not bring the article back: %v", err)
+	}
+	if status := mocktest.Call(t, "POST", server.URL+"/token/refresh", "", mock.Tokens{RefreshToken: tokens.RefreshToken}, nil); status != http.StatusUnauthorized {
+		t.Errorf("refresh token from before the restore: expected status 401, got %d", status)
+	}
+
+	if status := adminCall(t, "POST", server.URL+"/admin/reset", "admin-key", nil); status != http.StatusOK {
+		t.Fatalf("reset: expected status 200, got %d", status)
+	}
+	authors, _ := mock.Authors.All()
+	articles, _ := mock.Articles.All()
+	if len(authors) != len(mock.SeedAuthors) || len(articles) != len(mock.SeedArticles) {
+		t.Errorf("reset left %d authors and %d articles", len(authors), len(articles))
+	}
+
+	if status := adminCall(t, "DELETE", server.URL+"/admin/snapshots/before", "admin-key", nil); status != http.StatusNoContent {
+		t.Errorf("delete snapshot: expected status 204, got %d", status)
+	}
+	if status := adminCall(t, "POST", server.URL+"/admin/snapshots/before/restore", "admin-key", nil); status != http.StatusNotFound {
+		t.Errorf("restore deleted snapshot: expected status 404, got %d", status)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock/mocktest"
+	"github.com/Bone1289/go-web-example/mock/rest"
+	"github.com/gorilla/mux"
+)
+
+func TestAuthorizationHeader(t *testing.T) {
+	server := newServer(t, "memory")
+	_, token := mocktest.CreateAndLogin(t, server, "header")
+
+	headers := []struct {
+		header  string
+		status  int
+		invalid bool
+	}{
+		{"", http.StatusUnauthorized, false},
+		{"Bearer", http.StatusUnauthorized, true},
+		{"Bearer ", http.StatusUnauthorized, true},
+		{"Token a b", http.StatusUnauthorized, true},
+		{"Basic " + token, http.StatusUnauthorized, true},
+		{"Bearer " + token + " extra", http.StatusUnauthorized, true},
+		{"Bearer not-a-token", http.StatusUnauthorized, true},
+		{"Bearer " + token, http.StatusCreated, false},
+		{"bearer " + token, http.StatusCreated, false},
+		{"BEARER  " + token, http.StatusUnauthorized, true},
+	}
+	for _, test := range headers {
+		request, _ := http.NewRequest("POST", server.URL+"/article", strings.NewReader(`{"title":"title","content":"content"}`))
+		if test.header != "" {
+			request.Header.Set("authorization", test.header)
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		response.Body.Close()
+		if response.StatusCode != test.status {
+			t.Errorf("%q: expected status %d, got %d", test.header, test.status, response.StatusCode)
+		}
+		challenge := response.Header.Get("WWW-Authenticate")
+		switch {
+		case test.status != http.StatusUnauthorized:
+			if challenge != "" {
+				t.Errorf("%q: unexpected challenge %q", test.header, challenge)
+			}
+		case !strings.HasPrefix(challenge, "Bearer "):
+			t.Errorf("%q: expected a Bearer challenge, got %q", test.header, challenge)
+		case strings.Contains(challenge, "invalid_token") != test.invalid:
+			t.Errorf("%q: unexpected challenge %q", test.header, challenge)
+		}
+	}
+}
+
+// TestHandlersWithoutMiddleware mounts the protected handlers without
+// ValidateMiddleware, which must be answered with a 401 rather than a panic.
+func TestHandlersWithoutMiddleware(t *testing.T) {
+	newServer(t, "memory")
+	handlers := map[string]http.HandlerFunc{
+		"POST /article":        rest.ArticleCreateEndpoint,
+		"PUT /article/{id}":    rest.ArticleUpdateEndpoint,
+		"DELETE /article/{id}": rest.ArticleDeleteEndpoint,
+		"PUT /author/{id}":     rest.AuthorUpdateEndpoint,
+		"DELETE /author/{id}":  rest.AuthorDeleteEndpoint,
+	}
+	for route, handler := range handlers {
+		parts := strings.SplitN(route, " ", 2)
+		router := mux.NewRouter()
+		router.HandleFunc(parts[1], handler).Methods(parts[0])
+		path := strings.Replace(parts[1], "{id}", "some-id", 1)
+		request := httptest.NewRequest(parts[0], path, strings.NewReader(`{"title":"title"}`))
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		if recorder.Code != http.StatusUnauthorized {
+			t.Errorf("%s: expected status 401, got %d", route, recorder.Code)
+		}
+	}
+}
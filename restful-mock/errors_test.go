@@ -0,0 +1,70 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/Bone1289/go-web-example/mock/mocktest"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestErrorResponses(t *testing.T) {
+	cost := mock.BcryptCost
+	mock.BcryptCost = bcrypt.MinCost
+	t.Cleanup(func() { mock.BcryptCost = cost })
+	server := newServer(t, "memory")
+	_, token := mocktest.CreateAndLogin(t, server, "errors")
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		body    interface{}
+		status  int
+		code    string
+		details []mock.FieldError
+	}{
+		{"malformed body", "POST", "/author", "not an author", http.StatusBadRequest, "bad_request", nil},
+		{"unknown sort field", "GET", "/articles?sort=unknown", nil, http.StatusBadRequest, "bad_request", nil},
+		{"createdAt filter", "GET", "/articles?createdAt=2020-01-03T00:00:00Z", nil, http.StatusBadRequest, "bad_request", nil},
+		{"missing article", "GET", "/article/missing", nil, http.StatusNotFound, "not_found", nil},
+		{"missing author", "GET", "/author/missing", nil, http.StatusNotFound, "not_found", nil},
+		{"taken username", "POST", "/author", mock.Author{Firstname: "F", Lastname: "L", Username: "errors", Password: "secret"}, http.StatusConflict, "conflict", nil},
+		{"wrong password", "POST", "/login", mock.Author{Username: "errors", Password: "wrong"}, http.StatusUnauthorized, "invalid_credentials", nil},
+		{
+			"invalid author", "POST", "/author", mock.Author{Lastname: "L", Username: "short", Password: "abc"},
+			http.StatusUnprocessableEntity, "validation_failed",
+			[]mock.FieldError{{Field: "firstname", Rule: "required"}, {Field: "password", Rule: "gte", Param: "4"}},
+		},
+		{
+			"article without a title", "POST", "/article", mock.Article{Content: "content"},
+			http.StatusUnprocessableEntity, "validation_failed",
+			[]mock.FieldError{{Field: "title", Rule: "required"}},
+		},
+	}
+	for _, test := range tests {
+		var body mock.Error
+		status := mocktest.Call(t, test.method, server.URL+test.path, token, test.body, &body)
+		if status != test.status {
+			t.Errorf("%s: expected status %d, got %d", test.name, test.status, status)
+		}
+		if body.Code != test.code || body.Message == "" {
+			t.Errorf("%s: expected code %q with a message, got %+v", test.name, test.code, body)
+		}
+		if len(body.Details) != len(test.details) {
+			t.Errorf("%s: expected details %+v, got %+v", test.name, test.details, body.Details)
+			continue
+		}
+		for index, detail := range body.Details {
+			expected := test.details[index]
+			if detail.Field != expected.Field || detail.Rule != expected.Rule || detail.Param != expected.Param {
+				t.Errorf("%s: expected detail %+v, got %+v", test.name, expected, detail)
+			}
+			if !strings.Contains(detail.Message, detail.Field) {
+				t.Errorf("%s: message %q does not name the field", test.name, detail.Message)
+			}
+		}
+	}
+}
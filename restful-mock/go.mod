@@ -1,16 +1,24 @@
 module github.com/Bone1289/go-web-example/restful-mock
 
-go 1.14
+go 1.18
 
 require (
-	github.com/dgrijalva/jwt-go v3.2.0+incompatible
-	github.com/go-playground/universal-translator v0.17.0 // indirect
-	github.com/gorilla/context v1.1.1
-	github.com/gorilla/handlers v1.4.2
+	github.com/Bone1289/go-web-example/mock v0.0.0-00010101000000-000000000000
+	github.com/golang-jwt/jwt/v5 v5.2.3
 	github.com/gorilla/mux v1.7.4
-	github.com/leodido/go-urn v1.2.0 // indirect
-	github.com/mitchellh/mapstructure v1.3.3
-	github.com/satori/go.uuid v1.2.1-0.20181028125025-b2ce2384e17b
 	golang.org/x/crypto v0.0.0-20200728195943-123391ffb6de
-	gopkg.in/go-playground/validator.v9 v9.31.0
 )
+
+require (
+	github.com/go-playground/locales v0.13.0 // indirect
+	github.com/go-playground/universal-translator v0.17.0 // indirect
+	github.com/gorilla/handlers v1.4.2 // indirect
+	github.com/graphql-go/graphql v0.7.9 // indirect
+	github.com/leodido/go-urn v1.2.0 // indirect
+	github.com/mitchellh/mapstructure v1.3.3 // indirect
+	github.com/satori/go.uuid v1.2.1-0.20181028125025-b2ce2384e17b // indirect
+	gopkg.in/go-playground/validator.v9 v9.31.0 // indirect
+	gopkg.in/yaml.v2 v2.4.0 // indirect
+)
+
+replace github.com/Bone1289/go-web-example/mock => ../mock
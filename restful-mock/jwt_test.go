@@ -0,0 +1,81 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/Bone1289/go-web-example/mock/mocktest"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestTokenClaimValidation(t *testing.T) {
+	server := newServer(t, "memory")
+	author, _ := mocktest.CreateAndLogin(t, server, "claims")
+	now := time.Now()
+
+	// valid returns the claims NewToken would issue, for the cases to break.
+	valid := func() jwt.RegisteredClaims {
+		return jwt.RegisteredClaims{
+			Issuer:    mock.JwtIssuer,
+			Audience:  jwt.ClaimStrings{mock.JwtAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		}
+	}
+	tests := []struct {
+		name   string
+		change func(claims *jwt.RegisteredClaims)
+		status int
+	}{
+		{"valid", func(claims *jwt.RegisteredClaims) {}, http.StatusCreated},
+		{"wrong issuer", func(claims *jwt.RegisteredClaims) { claims.Issuer = "someone else" }, http.StatusUnauthorized},
+		{"no issuer", func(claims *jwt.RegisteredClaims) { claims.Issuer = "" }, http.StatusUnauthorized},
+		{"wrong audience", func(claims *jwt.RegisteredClaims) { claims.Audience = jwt.ClaimStrings{"another-api"} }, http.StatusUnauthorized},
+		{"no audience", func(claims *jwt.RegisteredClaims) { claims.Audience = nil }, http.StatusUnauthorized},
+		{"expired", func(claims *jwt.RegisteredClaims) { claims.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Minute)) }, http.StatusUnauthorized},
+		{"expired within skew", func(claims *jwt.RegisteredClaims) {
+			claims.ExpiresAt = jwt.NewNumericDate(now.Add(-mock.ClockSkew / 2))
+		}, http.StatusCreated},
+		{"no expiry", func(claims *jwt.RegisteredClaims) { claims.ExpiresAt = nil }, http.StatusUnauthorized},
+		{"not yet valid", func(claims *jwt.RegisteredClaims) { claims.NotBefore = jwt.NewNumericDate(now.Add(time.Minute)) }, http.StatusUnauthorized},
+		{"no not before", func(claims *jwt.RegisteredClaims) { claims.NotBefore = nil }, http.StatusUnauthorized},
+		{"issued in the future", func(claims *jwt.RegisteredClaims) { claims.IssuedAt = jwt.NewNumericDate(now.Add(time.Minute)) }, http.StatusUnauthorized},
+		{"issued within skew", func(claims *jwt.RegisteredClaims) { claims.IssuedAt = jwt.NewNumericDate(now.Add(mock.ClockSkew / 2)) }, http.StatusCreated},
+		{"no issued at", func(claims *jwt.RegisteredClaims) { claims.IssuedAt = nil }, http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		claims := mock.CustomJWTClaims{Id: author.Id, RegisteredClaims: valid()}
+		test.change(&claims.RegisteredClaims)
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(mock.JwtSecret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		status := mocktest.Call(t, "POST", server.URL+"/article", token, mock.Article{Title: "title", Content: "content"}, nil)
+		if status != test.status {
+			t.Errorf("%s: expected status %d, got %d", test.name, test.status, status)
+		}
+	}
+}
+
+// TestRevokedWithinSkew logs out a token, lets it expire while still inside
+// the clock skew, and expects it to stay revoked after the next prune.
+func TestRevokedWithinSkew(t *testing.T) {
+	server := newServer(t, "memory")
+	lifetime, skew := mock.TokenLifetime, mock.ClockSkew
+	t.Cleanup(func() { mock.TokenLifetime, mock.ClockSkew = lifetime, skew })
+	mock.TokenLifetime, mock.ClockSkew = time.Second, 10*time.Second
+	mocktest.CreateAndLogin(t, server, "skew")
+	tokens := login(t, server, "skew")
+	if status := mocktest.Call(t, "POST", server.URL+"/logout", tokens.Token, nil, nil); status != http.StatusNoContent {
+		t.Fatalf("logout: expected status 204, got %d", status)
+	}
+
+	time.Sleep(2 * time.Second)
+	login(t, server, "skew")
+	if _, err := mock.ValidateJWT(tokens.Token); err == nil {
+		t.Error("logged out token validates once expired within the clock skew")
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/Bone1289/go-web-example/mock/mocktest"
+)
+
+// useKeys installs a signing key and the keys accepted for verification for
+// the rest of the test.
+func useKeys(t *testing.T, signing *mock.Key, verification ...*mock.Key) {
+	signingKey, verificationKeys := mock.SigningKey, mock.VerificationKeys
+	t.Cleanup(func() { mock.SigningKey, mock.VerificationKeys = signingKey, verificationKeys })
+	mock.SigningKey, mock.VerificationKeys = signing, verification
+}
+
+func TestKeyRotation(t *testing.T) {
+	server := newServer(t, "memory")
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	retired := mocktest.Key(t, rsaKey)
+	retiredPublic := mocktest.Key(t, &rsaKey.PublicKey)
+	current := mocktest.Key(t, edKey)
+	unknown := mocktest.Key(t, ecKey)
+
+	author, _ := mocktest.CreateAndLogin(t, server, "rotation")
+	hmacToken, _ := mock.NewToken(author)
+	useKeys(t, retired, retired)
+	retiredToken, _ := mock.NewToken(author)
+	useKeys(t, unknown, unknown)
+	unknownToken, _ := mock.NewToken(author)
+
+	useKeys(t, current, current, retiredPublic)
+	currentToken, _ := mock.NewToken(author)
+	tests := []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{"token of the signing key", currentToken, http.StatusCreated},
+		{"token of a retired key", retiredToken, http.StatusCreated},
+		{"token of an unknown key", unknownToken, http.StatusUnauthorized},
+		{"HS256 token", hmacToken, http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		if status := createArticle(t, server, test.token); status != test.status {
+			t.Errorf("%s: expected status %d, got %d", test.name, test.status, status)
+		}
+	}
+
+	var jwks struct {
+		Keys []mock.JWK `json:"keys"`
+	}
+	response, err := http.Get(server.URL + "/.well-known/jwks.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	json.NewDecoder(response.Body).Decode(&jwks)
+	response.Body.Close()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("expected 2 keys, got %+v", jwks.Keys)
+	}
+	okp, rsaJWK := jwks.Keys[0], jwks.Keys[1]
+	if okp.Kid != current.Id || okp.Kty != "OKP" || okp.Crv != "Ed25519" || okp.Alg != "EdDSA" || okp.Use != "sig" || okp.X == "" {
+		t.Errorf("unexpected signing key %+v", okp)
+	}
+	if rsaJWK.Kid != retired.Id || rsaJWK.Kty != "RSA" || rsaJWK.Alg != "RS256" || rsaJWK.E != "AQAB" || rsaJWK.N == "" {
+		t.Errorf("unexpected retired key %+v", rsaJWK)
+	}
+
+	useKeys(t, current, current)
+	if status := createArticle(t, server, retiredToken); status != http.StatusUnauthorized {
+		t.Errorf("token of a key no longer accepted: expected status 401, got %d", status)
+	}
+}
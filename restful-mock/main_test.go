@@ -0,0 +1,13 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock/mocktest"
+)
+
+// newServer serves the REST API over new seeded stores of kind.
+func newServer(t *testing.T, kind string) *httptest.Server {
+	return mocktest.NewServer(t, kind, NewRouter())
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/Bone1289/go-web-example/mock/mocktest"
+)
+
+func TestAuthorOwnership(t *testing.T) {
+	server := newServer(t, "memory")
+	owner, ownerToken := mocktest.CreateAndLogin(t, server, "owner")
+	_, otherToken := mocktest.CreateAndLogin(t, server, "other")
+
+	tests := []struct {
+		name   string
+		method string
+		token  string
+		status int
+	}{
+		{"update without a token", "PUT", "", http.StatusUnauthorized},
+		{"delete without a token", "DELETE", "", http.StatusUnauthorized},
+		{"update by another author", "PUT", otherToken, http.StatusForbidden},
+		{"delete by another author", "DELETE", otherToken, http.StatusForbidden},
+		{"update by the owner", "PUT", ownerToken, http.StatusOK},
+		{"delete by the owner", "DELETE", ownerToken, http.StatusOK},
+	}
+	for _, test := range tests {
+		var body mock.Error
+		status := mocktest.Call(t, test.method, server.URL+"/author/"+owner.Id, test.token, mock.Author{Firstname: "Changed"}, &body)
+		if status != test.status {
+			t.Errorf("%s: expected status %d, got %d (%+v)", test.name, test.status, status, body)
+		}
+	}
+	if _, err := mock.Authors.Get(owner.Id); err != mock.ErrNotFound {
+		t.Errorf("owner could not delete themselves: %v", err)
+	}
+}
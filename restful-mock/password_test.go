@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/Bone1289/go-web-example/mock/mocktest"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// assertNoPassword fails when a response body carries a password field or
+// anything that looks like a bcrypt hash.
+func assertNoPassword(t *testing.T, endpoint string, body json.RawMessage) {
+	t.Helper()
+	if strings.Contains(string(body), `"password"`) || strings.Contains(string(body), "$2a$") {
+		t.Errorf("%s exposed a password: %s", endpoint, body)
+	}
+}
+
+func TestNoPasswordInResponses(t *testing.T) {
+	cost := mock.BcryptCost
+	mock.BcryptCost = bcrypt.MinCost
+	t.Cleanup(func() { mock.BcryptCost = cost })
+	server := newServer(t, "memory")
+
+	var body json.RawMessage
+	mocktest.Call(t, "POST", server.URL+"/author", "", mock.Author{Firstname: "New", Lastname: "Author", Username: "new", Password: "secret"}, &body)
+	assertNoPassword(t, "POST /author", body)
+
+	author, token := mocktest.CreateAndLogin(t, server, "owner")
+	mocktest.Call(t, "POST", server.URL+"/login", "", mock.Author{Username: "owner", Password: "secret"}, &body)
+	assertNoPassword(t, "POST /login", body)
+
+	requests := []struct {
+		method string
+		path   string
+		body   interface{}
+	}{
+		{"GET", "/authors", nil},
+		{"GET", "/authors?sort=-username&limit=2", nil},
+		{"GET", "/author/" + author.Id, nil},
+		{"PUT", "/author/" + author.Id, mock.Author{Password: "changed"}},
+		{"POST", "/article", mock.Article{Title: "title", Content: "content"}},
+		{"GET", "/articles", nil},
+		{"DELETE", "/author/" + author.Id, nil},
+	}
+	for _, request := range requests {
+		body = nil
+		mocktest.Call(t, request.method, server.URL+request.path, token, request.body, &body)
+		assertNoPassword(t, request.method+" "+request.path, body)
+	}
+}
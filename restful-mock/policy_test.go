@@ -0,0 +1,47 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/Bone1289/go-web-example/mock/mocktest"
+)
+
+func TestPolicy(t *testing.T) {
+	server := newServer(t, "memory")
+	owner, ownerToken := mocktest.CreateAndLogin(t, server, "owner")
+	_, readerToken := mocktest.CreateWithRole(t, server, "reader", mock.RoleReader)
+	_, editorToken := mocktest.CreateWithRole(t, server, "editor", mock.RoleEditor)
+	_, adminToken := mocktest.CreateWithRole(t, server, "admin", mock.RoleAdmin)
+	var article mock.Article
+	if status := mocktest.Call(t, "POST", server.URL+"/article", ownerToken, mock.Article{Title: "title", Content: "content"}, &article); status != http.StatusCreated {
+		t.Fatalf("create article: expected status 201, got %d", status)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		body   interface{}
+		status int
+	}{
+		{"reader creates an article", "POST", "/article", readerToken, mock.Article{Title: "title", Content: "content"}, http.StatusForbidden},
+		{"editor updates another author's article", "PUT", "/article/" + article.Id, editorToken, mock.Article{Title: "edited"}, http.StatusOK},
+		{"author changes their own role", "PUT", "/author/" + owner.Id, ownerToken, mock.Author{Role: mock.RoleAdmin}, http.StatusForbidden},
+		{"admin deletes another author", "DELETE", "/author/" + owner.Id, adminToken, nil, http.StatusOK},
+	}
+	for _, test := range tests {
+		var body mock.Error
+		if status := mocktest.Call(t, test.method, server.URL+test.path, test.token, test.body, &body); status != test.status {
+			t.Errorf("%s: expected status %d, got %d (%+v)", test.name, test.status, status, body)
+		}
+	}
+	if edited, _ := mock.Articles.Get(article.Id); edited.Title != "edited" {
+		t.Errorf("editor's update was not stored: %+v", edited)
+	}
+	if _, err := mock.Authors.Get(owner.Id); err != mock.ErrNotFound {
+		t.Errorf("admin could not delete the author: %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+)
+
+// linkRels parses a Link header into its targets by rel.
+func linkRels(header string) map[string]url.Values {
+	rels := map[string]url.Values{}
+	for _, link := range strings.Split(header, ", ") {
+		parts := strings.SplitN(link, "; ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		target, _ := url.Parse(strings.Trim(parts[0], "<>"))
+		rel := strings.TrimSuffix(strings.TrimPrefix(parts[1], `rel="`), `"`)
+		rels[rel] = target.Query()
+	}
+	return rels
+}
+
+func TestPageHeaders(t *testing.T) {
+	server := newServer(t, "memory")
+	mock.Authors.Replace([]mock.Author{
+		{Id: "a", Username: "Alice", Firstname: "Alice", Lastname: "red"},
+		{Id: "b", Username: "Bob", Firstname: "Bob", Lastname: "blue"},
+		{Id: "c", Username: "Carol", Firstname: "Carol", Lastname: "red"},
+		{Id: "d", Username: "Dave", Firstname: "Dave", Lastname: "blue"},
+		{Id: "e", Username: "Eve", Firstname: "Eve", Lastname: "red"},
+	})
+
+	get := func(path string) (*http.Response, []mock.PublicAuthor) {
+		var page []mock.PublicAuthor
+		response, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer response.Body.Close()
+		json.NewDecoder(response.Body).Decode(&page)
+		return response, page
+	}
+
+	response, page := get("/authors?lastname=red")
+	if response.Header.Get("X-Total-Count") != "3" || len(page) != 3 {
+		t.Errorf("filtered page: total %q with %d authors", response.Header.Get("X-Total-Count"), len(page))
+	}
+	if link := response.Header.Get("Link"); link != "" {
+		t.Errorf("unlimited page has links: %s", link)
+	}
+
+	response, page = get("/authors?limit=2&offset=2&sort=username")
+	rels := linkRels(response.Header.Get("Link"))
+	if response.Header.Get("X-Total-Count") != "5" || len(page) != 2 || page[0].Id != "c" {
+		t.Errorf("offset page: total %q, page %+v", response.Header.Get("X-Total-Count"), page)
+	}
+	expected := map[string]string{"first": "", "prev": "0", "next": "4", "last": "4"}
+	if len(rels) != len(expected) {
+		t.Errorf("offset page: expected rels %v, got %v", expected, rels)
+	}
+	for rel, offset := range expected {
+		values, ok := rels[rel]
+		if !ok || values.Get("offset") != offset || values.Get("limit") != "2" || values.Get("sort") != "username" {
+			t.Errorf("offset page: unexpected %s link %v", rel, values)
+		}
+	}
+
+	response, page = get("/authors?limit=2")
+	rels = linkRels(response.Header.Get("Link"))
+	if _, ok := rels["prev"]; ok || rels["next"].Get("after") == "" || len(rels) != 2 {
+		t.Fatalf("first cursor page: unexpected links %v", rels)
+	}
+	response, page = get("/authors?" + rels["next"].Encode())
+	rels = linkRels(response.Header.Get("Link"))
+	if len(page) != 2 || page[0].Id != "c" || rels["prev"].Get("before") == "" || rels["next"].Get("after") == "" {
+		t.Fatalf("second cursor page: page %+v, links %v", page, rels)
+	}
+	_, page = get("/authors?" + rels["prev"].Encode())
+	if len(page) != 2 || page[0].Id != "a" || page[1].Id != "b" {
+		t.Errorf("prev link: unexpected page %+v", page)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/Bone1289/go-web-example/mock/mocktest"
+)
+
+// login signs in an author created by mocktest.CreateAndLogin and returns both tokens.
+func login(t *testing.T, server *httptest.Server, username string) mock.Tokens {
+	var tokens mock.Tokens
+	mocktest.Call(t, "POST", server.URL+"/login", "", mock.Author{Username: username, Password: "secret"}, &tokens)
+	if tokens.Token == "" || tokens.RefreshToken == "" {
+		t.Fatalf("login for %s failed: %+v", username, tokens)
+	}
+	return tokens
+}
+
+func refresh(t *testing.T, server *httptest.Server, refreshToken string) (mock.Tokens, int) {
+	var tokens mock.Tokens
+	status := mocktest.Call(t, "POST", server.URL+"/token/refresh", "", mock.Tokens{RefreshToken: refreshToken}, &tokens)
+	return tokens, status
+}
+
+func createArticle(t *testing.T, server *httptest.Server, token string) int {
+	return mocktest.Call(t, "POST", server.URL+"/article", token, mock.Article{Title: "title", Content: "content"}, nil)
+}
+
+func TestRefreshRotation(t *testing.T) {
+	server := newServer(t, "memory")
+	mocktest.CreateAndLogin(t, server, "rotation")
+	tokens := login(t, server, "rotation")
+
+	rotated, status := refresh(t, server, tokens.RefreshToken)
+	if status != http.StatusOK || rotated.Token == "" || rotated.RefreshToken == "" || rotated.RefreshToken == tokens.RefreshToken {
+		t.Fatalf("refresh: status %d, tokens %+v", status, rotated)
+	}
+	if status := createArticle(t, server, rotated.Token); status != http.StatusCreated {
+		t.Errorf("refreshed access token: expected status 201, got %d", status)
+	}
+	again, status := refresh(t, server, rotated.RefreshToken)
+	if status != http.StatusOK {
+		t.Fatalf("second refresh: expected status 200, got %d", status)
+	}
+
+	// Presenting a retired refresh token revokes every token of its family,
+	// including the one handed out last.
+	if _, status := refresh(t, server, tokens.RefreshToken); status != http.StatusUnauthorized {
+		t.Errorf("reused refresh token: expected status 401, got %d", status)
+	}
+	if _, status := refresh(t, server, again.RefreshToken); status != http.StatusUnauthorized {
+		t.Errorf("refresh token of a revoked family: expected status 401, got %d", status)
+	}
+	for _, refreshToken := range []string{"", "unknown"} {
+		if _, status := refresh(t, server, refreshToken); status != http.StatusUnauthorized {
+			t.Errorf("refresh token %q: expected status 401, got %d", refreshToken, status)
+		}
+	}
+}
+
+func TestRefreshExpiry(t *testing.T) {
+	server := newServer(t, "memory")
+	lifetime := mock.RefreshTokenLifetime
+	t.Cleanup(func() { mock.RefreshTokenLifetime = lifetime })
+	mock.RefreshTokenLifetime = 10 * time.Millisecond
+	mocktest.CreateAndLogin(t, server, "expiry")
+	tokens := login(t, server, "expiry")
+
+	time.Sleep(20 * time.Millisecond)
+	if _, status := refresh(t, server, tokens.RefreshToken); status != http.StatusUnauthorized {
+		t.Errorf("expired refresh token: expected status 401, got %d", status)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	server := newServer(t, "memory")
+	mocktest.CreateAndLogin(t, server, "owner")
+	mocktest.CreateAndLogin(t, server, "other")
+	owner := login(t, server, "owner")
+	other := login(t, server, "other")
+
+	if status := mocktest.Call(t, "POST", server.URL+"/logout", "", mock.Tokens{RefreshToken: owner.RefreshToken}, nil); status != http.StatusUnauthorized {
+		t.Errorf("logout without a token: expected status 401, got %d", status)
+	}
+
+	// Another author cannot end the owner's refresh session, but is logged
+	// out themselves.
+	if status := mocktest.Call(t, "POST", server.URL+"/logout", other.Token, mock.Tokens{RefreshToken: owner.RefreshToken}, nil); status != http.StatusNoContent {
+		t.Fatalf("logout with another author's refresh token: expected status 204, got %d", status)
+	}
+	if _, err := mock.ValidateJWT(other.Token); err == nil {
+		t.Error("logged out access token still validates")
+	}
+	if status := createArticle(t, server, other.Token); status != http.StatusUnauthorized {
+		t.Errorf("logged out access token: expected status 401, got %d", status)
+	}
+	owner, status := refresh(t, server, owner.RefreshToken)
+	if status != http.StatusOK {
+		t.Fatalf("refresh token passed by another author: expected status 200, got %d", status)
+	}
+
+	if status := mocktest.Call(t, "POST", server.URL+"/logout", owner.Token, mock.Tokens{RefreshToken: owner.RefreshToken}, nil); status != http.StatusNoContent {
+		t.Fatalf("logout: expected status 204, got %d", status)
+	}
+	if status := createArticle(t, server, owner.Token); status != http.StatusUnauthorized {
+		t.Errorf("logged out access token: expected status 401, got %d", status)
+	}
+	if _, status := refresh(t, server, owner.RefreshToken); status != http.StatusUnauthorized {
+		t.Errorf("logged out refresh token: expected status 401, got %d", status)
+	}
+	if status := mocktest.Call(t, "POST", server.URL+"/logout", owner.Token, nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("second logout: expected status 401, got %d", status)
+	}
+}
+
+// TestLogoutSurvivesRestart reopens the file store, as a restart would, and
+// expects the logged out token to stay revoked.
+func TestLogoutSurvivesRestart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "restful-mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	mock.Authors, mock.Articles, err = mock.OpenStores("file", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(NewRouter())
+	defer server.Close()
+	mocktest.CreateAndLogin(t, server, "restart")
+	tokens := login(t, server, "restart")
+	kept := login(t, server, "restart")
+	if status := mocktest.Call(t, "POST", server.URL+"/logout", tokens.Token, nil, nil); status != http.StatusNoContent {
+		t.Fatalf("logout: expected status 204, got %d", status)
+	}
+
+	mock.Authors, mock.Articles, err = mock.OpenStores("file", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mock.ValidateJWT(tokens.Token); err == nil {
+		t.Error("logged out access token validates after a restart")
+	}
+	if _, err := mock.ValidateJWT(kept.Token); err != nil {
+		t.Errorf("access token that was not logged out fails after a restart: %v", err)
+	}
+}
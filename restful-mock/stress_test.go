@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/Bone1289/go-web-example/mock"
+	"github.com/Bone1289/go-web-example/mock/mocktest"
+)
+
+const stressWorkers = 16
+const stressRounds = 10
+
+func testConcurrentArticles(t *testing.T, kind string) {
+	server := newServer(t, kind)
+	tokens := make([]string, stressWorkers)
+	for worker := range tokens {
+		_, tokens[worker] = mocktest.CreateAndLogin(t, server, fmt.Sprintf("stress-%d", worker))
+	}
+
+	var wait sync.WaitGroup
+	for worker := 0; worker < stressWorkers; worker++ {
+		wait.Add(1)
+		go func(worker int) {
+			defer wait.Done()
+			token := tokens[worker]
+			for round := 0; round < stressRounds; round++ {
+				var created mock.Article
+				mocktest.Call(t, "POST", server.URL+"/article", token, mock.Article{Title: "title", Content: "content"}, &created)
+				if created.Id == "" {
+					t.Errorf("worker %d round %d: article not created", worker, round)
+					continue
+				}
+				mocktest.Call(t, "PUT", server.URL+"/article/"+created.Id, token, mock.Article{Title: fmt.Sprintf("title-%d", round)}, nil)
+				mocktest.Call(t, "GET", server.URL+"/articles", "", nil, nil)
+				mocktest.Call(t, "GET", server.URL+"/article/"+created.Id, "", nil, nil)
+				if round%2 == 0 {
+					mocktest.Call(t, "DELETE", server.URL+"/article/"+created.Id, token, nil, nil)
+				}
+			}
+		}(worker)
+	}
+	wait.Wait()
+
+	articles, _ := mock.Articles.All()
+	expected := len(mock.SeedArticles) + stressWorkers*stressRounds/2
+	if len(articles) != expected {
+		t.Fatalf("expected %d articles, got %d", expected, len(articles))
+	}
+	for _, article := range articles {
+		if article.Id != "article-1" && article.Title == "title" {
+			t.Errorf("update to article %s was lost", article.Id)
+		}
+	}
+}
+
+func testConcurrentAuthors(t *testing.T, kind string) {
+	server := newServer(t, kind)
+	authors := make([]mock.Author, stressWorkers)
+	tokens := make([]string, stressWorkers)
+	for worker := range authors {
+		authors[worker], tokens[worker] = mocktest.CreateAndLogin(t, server, fmt.Sprintf("author-%d", worker))
+	}
+
+	var wait sync.WaitGroup
+	for index, author := range authors {
+		wait.Add(2)
+		go func(author mock.Author, token string) {
+			defer wait.Done()
+			mocktest.Call(t, "PUT", server.URL+"/author/"+author.Id, token, mock.Author{Firstname: "First"}, nil)
+			mocktest.Call(t, "GET", server.URL+"/authors", "", nil, nil)
+		}(author, tokens[index])
+		go func(index int, author mock.Author, token string) {
+			defer wait.Done()
+			mocktest.Call(t, "PUT", server.URL+"/author/"+author.Id, token, mock.Author{Lastname: "Last"}, nil)
+			if index%2 == 0 {
+				mocktest.Call(t, "DELETE", server.URL+"/author/"+author.Id, token, nil, nil)
+			}
+		}(index, author, tokens[index])
+	}
+	wait.Wait()
+
+	remaining, _ := mock.Authors.All()
+	expected := len(mock.SeedAuthors) + len(authors)/2
+	if len(remaining) != expected {
+		t.Fatalf("expected %d authors, got %d", expected, len(remaining))
+	}
+	for _, author := range remaining[len(mock.SeedAuthors):] {
+		if author.Firstname != "First" || author.Lastname != "Last" {
+			t.Errorf("lost update on author %s: %+v", author.Id, author)
+		}
+	}
+}
+
+func TestConcurrentArticlesMemory(t *testing.T) {
+	testConcurrentArticles(t, "memory")
+}
+
+func TestConcurrentArticlesFile(t *testing.T) {
+	testConcurrentArticles(t, "file")
+}
+
+func TestConcurrentAuthorsMemory(t *testing.T) {
+	testConcurrentAuthors(t, "memory")
+}
+
+func TestConcurrentAuthorsFile(t *testing.T) {
+	testConcurrentAuthors(t, "file")
+}